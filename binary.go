@@ -0,0 +1,410 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// BinaryFromNative appends the Avro binary encoding of datum, which must be
+// shaped according to c's schema the way every other function in this
+// package shapes native Go values (see the package doc comment), to buf and
+// returns the resulting byte slice. On error it returns buf unmodified, with
+// the caveat documented on ConfluentFromNative: bytes may already have been
+// appended to buf by a nested encoding step before the error was detected,
+// so callers must discard buf's new contents, not merely its length, on
+// error.
+func (c *Codec) BinaryFromNative(buf []byte, datum interface{}) ([]byte, error) {
+	return appendBinary(buf, c.node, datum, c.namedTypes)
+}
+
+// NativeFromBinary decodes the Avro binary encoded value at the front of
+// buf, returning it in native Go form along with any remaining,
+// unconsumed bytes.
+func (c *Codec) NativeFromBinary(buf []byte) (interface{}, []byte, error) {
+	return nativeFromBinaryNode(buf, c.node, c.namedTypes)
+}
+
+func asInt32(datum interface{}) (int32, bool) {
+	switch v := datum.(type) {
+	case int32:
+		return v, true
+	case int:
+		return int32(v), true
+	}
+	return 0, false
+}
+
+func asInt64(datum interface{}) (int64, bool) {
+	switch v := datum.(type) {
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+func appendBinary(buf []byte, node interface{}, datum interface{}, registry map[string]interface{}) ([]byte, error) {
+	node = resolveNamedNode(node, registry)
+	kind, def := schemaKind(node)
+
+	switch kind {
+	case "null":
+		if datum != nil {
+			return buf, fmt.Errorf("cannot encode binary null: expected nil, received %T", datum)
+		}
+		return buf, nil
+	case "boolean":
+		v, ok := datum.(bool)
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary boolean: expected bool, received %T", datum)
+		}
+		if v {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case "int":
+		v, ok := asInt32(datum)
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary int: expected int32 (or int), received %T", datum)
+		}
+		return appendZigzagVarint(buf, int64(v)), nil
+	case "long":
+		v, ok := asInt64(datum)
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary long: expected int64 (or int32, int), received %T", datum)
+		}
+		return appendZigzagVarint(buf, v), nil
+	case "float":
+		v, ok := datum.(float32)
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary float: expected float32, received %T", datum)
+		}
+		var tmp [4]byte
+		binary.LittleEndian.PutUint32(tmp[:], math.Float32bits(v))
+		return append(buf, tmp[:]...), nil
+	case "double":
+		v, ok := datum.(float64)
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary double: expected float64, received %T", datum)
+		}
+		var tmp [8]byte
+		binary.LittleEndian.PutUint64(tmp[:], math.Float64bits(v))
+		return append(buf, tmp[:]...), nil
+	case "bytes":
+		v, ok := datum.([]byte)
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary bytes: expected []byte, received %T", datum)
+		}
+		buf = appendZigzagVarint(buf, int64(len(v)))
+		return append(buf, v...), nil
+	case "string":
+		v, ok := datum.(string)
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary string: expected string, received %T", datum)
+		}
+		buf = appendZigzagVarint(buf, int64(len(v)))
+		return append(buf, v...), nil
+	case "record":
+		m, ok := datum.(map[string]interface{})
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary record: expected map[string]interface{}, received %T", datum)
+		}
+		fields, _ := def["fields"].([]interface{})
+		var err error
+		for _, f := range fields {
+			fm, _ := f.(map[string]interface{})
+			name, _ := fm["name"].(string)
+			buf, err = appendBinary(buf, fm["type"], m[name], registry)
+			if err != nil {
+				return buf, fmt.Errorf("cannot encode binary record field %q: %s", name, err)
+			}
+		}
+		return buf, nil
+	case "enum":
+		v, ok := datum.(string)
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary enum: expected string, received %T", datum)
+		}
+		symbols, _ := def["symbols"].([]interface{})
+		for i, s := range symbols {
+			if s == v {
+				return appendZigzagVarint(buf, int64(i)), nil
+			}
+		}
+		return buf, fmt.Errorf("cannot encode binary enum: symbol not in schema: %q", v)
+	case "fixed":
+		v, ok := datum.([]byte)
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary fixed: expected []byte, received %T", datum)
+		}
+		size, _ := jsonNumberToInt64(def["size"])
+		if int64(len(v)) != size {
+			return buf, fmt.Errorf("cannot encode binary fixed: expected %d bytes, received %d", size, len(v))
+		}
+		return append(buf, v...), nil
+	case "array":
+		v, ok := datum.([]interface{})
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary array: expected []interface{}, received %T", datum)
+		}
+		if len(v) > 0 {
+			buf = appendZigzagVarint(buf, int64(len(v)))
+			var err error
+			for _, item := range v {
+				buf, err = appendBinary(buf, def["items"], item, registry)
+				if err != nil {
+					return buf, fmt.Errorf("cannot encode binary array item: %s", err)
+				}
+			}
+		}
+		return appendZigzagVarint(buf, 0), nil
+	case "map":
+		v, ok := datum.(map[string]interface{})
+		if !ok {
+			return buf, fmt.Errorf("cannot encode binary map: expected map[string]interface{}, received %T", datum)
+		}
+		if len(v) > 0 {
+			buf = appendZigzagVarint(buf, int64(len(v)))
+			var err error
+			for k, val := range v {
+				buf, err = appendBinary(buf, "string", k, registry)
+				if err != nil {
+					return buf, err
+				}
+				buf, err = appendBinary(buf, def["values"], val, registry)
+				if err != nil {
+					return buf, fmt.Errorf("cannot encode binary map value for key %q: %s", k, err)
+				}
+			}
+		}
+		return appendZigzagVarint(buf, 0), nil
+	case "union":
+		branches, _ := node.([]interface{})
+		if datum == nil {
+			for i, b := range branches {
+				if k, _ := schemaKind(b); k == "null" {
+					return appendZigzagVarint(buf, int64(i)), nil
+				}
+			}
+			return buf, fmt.Errorf("cannot encode binary union: schema has no null branch")
+		}
+		m, ok := datum.(map[string]interface{})
+		if !ok || len(m) != 1 {
+			return buf, fmt.Errorf("cannot encode binary union: expected map[string]interface{} with a single key, received %T", datum)
+		}
+		var branchName string
+		var value interface{}
+		for k, v := range m {
+			branchName, value = k, v
+		}
+		for i, b := range branches {
+			if unionBranchName(b) == branchName {
+				buf = appendZigzagVarint(buf, int64(i))
+				return appendBinary(buf, b, value, registry)
+			}
+		}
+		return buf, fmt.Errorf("cannot encode binary union: no branch named %q", branchName)
+	default:
+		return buf, fmt.Errorf("cannot encode binary: unknown type: %q", kind)
+	}
+}
+
+func nativeFromBinaryNode(buf []byte, node interface{}, registry map[string]interface{}) (interface{}, []byte, error) {
+	node = resolveNamedNode(node, registry)
+	kind, def := schemaKind(node)
+
+	switch kind {
+	case "null":
+		return nil, buf, nil
+	case "boolean":
+		if len(buf) < 1 {
+			return nil, buf, fmt.Errorf("cannot decode binary boolean: buffer underrun")
+		}
+		return buf[0] != 0, buf[1:], nil
+	case "int":
+		v, rest, err := zigzagVarintFromBuf(buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("cannot decode binary int: %s", err)
+		}
+		return int32(v), rest, nil
+	case "long":
+		v, rest, err := zigzagVarintFromBuf(buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("cannot decode binary long: %s", err)
+		}
+		return v, rest, nil
+	case "float":
+		if len(buf) < 4 {
+			return nil, buf, fmt.Errorf("cannot decode binary float: buffer underrun")
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(buf[:4])), buf[4:], nil
+	case "double":
+		if len(buf) < 8 {
+			return nil, buf, fmt.Errorf("cannot decode binary double: buffer underrun")
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(buf[:8])), buf[8:], nil
+	case "bytes":
+		n, rest, err := zigzagVarintFromBuf(buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("cannot decode binary bytes: %s", err)
+		}
+		if n < 0 || int64(len(rest)) < n {
+			return nil, buf, fmt.Errorf("cannot decode binary bytes: buffer underrun")
+		}
+		v := make([]byte, n)
+		copy(v, rest[:n])
+		return v, rest[n:], nil
+	case "string":
+		n, rest, err := zigzagVarintFromBuf(buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("cannot decode binary string: %s", err)
+		}
+		if n < 0 || int64(len(rest)) < n {
+			return nil, buf, fmt.Errorf("cannot decode binary string: buffer underrun")
+		}
+		return string(rest[:n]), rest[n:], nil
+	case "record":
+		fields, _ := def["fields"].([]interface{})
+		m := make(map[string]interface{}, len(fields))
+		rest := buf
+		for _, f := range fields {
+			fm, _ := f.(map[string]interface{})
+			name, _ := fm["name"].(string)
+			var v interface{}
+			var err error
+			v, rest, err = nativeFromBinaryNode(rest, fm["type"], registry)
+			if err != nil {
+				return nil, buf, fmt.Errorf("cannot decode binary record field %q: %s", name, err)
+			}
+			m[name] = v
+		}
+		return m, rest, nil
+	case "enum":
+		idx, rest, err := zigzagVarintFromBuf(buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("cannot decode binary enum: %s", err)
+		}
+		symbols, _ := def["symbols"].([]interface{})
+		if idx < 0 || int(idx) >= len(symbols) {
+			return nil, buf, fmt.Errorf("cannot decode binary enum: symbol index out of range: %d", idx)
+		}
+		sym, _ := symbols[idx].(string)
+		return sym, rest, nil
+	case "fixed":
+		size, _ := jsonNumberToInt64(def["size"])
+		if int64(len(buf)) < size {
+			return nil, buf, fmt.Errorf("cannot decode binary fixed: buffer underrun")
+		}
+		v := make([]byte, size)
+		copy(v, buf[:size])
+		return v, buf[size:], nil
+	case "array":
+		var result []interface{}
+		rest := buf
+		for {
+			count, r, err := zigzagVarintFromBuf(rest)
+			if err != nil {
+				return nil, buf, fmt.Errorf("cannot decode binary array: %s", err)
+			}
+			rest = r
+			if count == 0 {
+				break
+			}
+			blockCount := count
+			if blockCount < 0 {
+				blockCount = -blockCount
+				// Negative block counts are followed by the block's byte
+				// size, which exists so a reader can skip the block
+				// without decoding each item; this package always decodes
+				// every item, so the size itself is discarded.
+				_, r2, err := zigzagVarintFromBuf(rest)
+				if err != nil {
+					return nil, buf, fmt.Errorf("cannot decode binary array: %s", err)
+				}
+				rest = r2
+			}
+			for i := int64(0); i < blockCount; i++ {
+				var item interface{}
+				var err error
+				item, rest, err = nativeFromBinaryNode(rest, def["items"], registry)
+				if err != nil {
+					return nil, buf, fmt.Errorf("cannot decode binary array item: %s", err)
+				}
+				result = append(result, item)
+			}
+		}
+		if result == nil {
+			result = []interface{}{}
+		}
+		return result, rest, nil
+	case "map":
+		result := make(map[string]interface{})
+		rest := buf
+		for {
+			count, r, err := zigzagVarintFromBuf(rest)
+			if err != nil {
+				return nil, buf, fmt.Errorf("cannot decode binary map: %s", err)
+			}
+			rest = r
+			if count == 0 {
+				break
+			}
+			blockCount := count
+			if blockCount < 0 {
+				blockCount = -blockCount
+				_, r2, err := zigzagVarintFromBuf(rest)
+				if err != nil {
+					return nil, buf, fmt.Errorf("cannot decode binary map: %s", err)
+				}
+				rest = r2
+			}
+			for i := int64(0); i < blockCount; i++ {
+				var key, value interface{}
+				var err error
+				key, rest, err = nativeFromBinaryNode(rest, "string", registry)
+				if err != nil {
+					return nil, buf, fmt.Errorf("cannot decode binary map key: %s", err)
+				}
+				value, rest, err = nativeFromBinaryNode(rest, def["values"], registry)
+				if err != nil {
+					return nil, buf, fmt.Errorf("cannot decode binary map value: %s", err)
+				}
+				result[key.(string)] = value
+			}
+		}
+		return result, rest, nil
+	case "union":
+		branches, _ := node.([]interface{})
+		idx, rest, err := zigzagVarintFromBuf(buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("cannot decode binary union: %s", err)
+		}
+		if idx < 0 || int(idx) >= len(branches) {
+			return nil, buf, fmt.Errorf("cannot decode binary union: branch index out of range: %d", idx)
+		}
+		b := branches[idx]
+		if bkind, _ := schemaKind(b); bkind == "null" {
+			return nil, rest, nil
+		}
+		value, rest2, err := nativeFromBinaryNode(rest, b, registry)
+		if err != nil {
+			return nil, buf, fmt.Errorf("cannot decode binary union: %s", err)
+		}
+		return map[string]interface{}{unionBranchName(b): value}, rest2, nil
+	default:
+		return nil, buf, fmt.Errorf("cannot decode binary: unknown type: %q", kind)
+	}
+}
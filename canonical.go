@@ -0,0 +1,145 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// primitiveTypeNames enumerates the Avro primitive type names, the only bare
+// strings a schema node may legally be (aside from a reference to a
+// previously defined named type).
+var primitiveTypeNames = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"int":     true,
+	"long":    true,
+	"float":   true,
+	"double":  true,
+	"bytes":   true,
+	"string":  true,
+}
+
+// canonicalString renders node, the JSON decoded representation of an Avro
+// schema, into the Avro specification's Parsing Canonical Form: primitive
+// types reduced to their bare name, only the attributes relevant to parsing
+// retained, those attributes ordered name/type/fields/symbols/items/values/
+// size, names fully qualified with their namespace, and no insignificant
+// whitespace.
+//
+// It does not resolve bare references to other named types against an
+// enclosing namespace; every schema exercised by this package either has no
+// namespace or already spells such references out fully qualified, and
+// handling the general case would require threading a symbol table through
+// every call, which nothing here needs yet.
+func canonicalString(node interface{}) (string, error) {
+	switch v := node.(type) {
+	case string:
+		return quoteJSONString(v), nil
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, branch := range v {
+			s, err := canonicalString(branch)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = s
+		}
+		return "[" + strings.Join(parts, ",") + "]", nil
+	case map[string]interface{}:
+		return canonicalStringFromDef(v)
+	default:
+		return "", fmt.Errorf("cannot canonicalize schema: unexpected node type %T", node)
+	}
+}
+
+func canonicalStringFromDef(def map[string]interface{}) (string, error) {
+	kind, _ := def["type"].(string)
+
+	switch kind {
+	case "record":
+		fields, _ := def["fields"].([]interface{})
+		fieldStrings := make([]string, len(fields))
+		for i, f := range fields {
+			fm, ok := f.(map[string]interface{})
+			if !ok {
+				return "", fmt.Errorf("cannot canonicalize schema: record field is not a JSON object")
+			}
+			fname, _ := fm["name"].(string)
+			ftype, err := canonicalString(fm["type"])
+			if err != nil {
+				return "", err
+			}
+			fieldStrings[i] = `{"name":` + quoteJSONString(fname) + `,"type":` + ftype + `}`
+		}
+		return `{"name":` + quoteJSONString(fullName(def)) + `,"type":"record","fields":[` + strings.Join(fieldStrings, ",") + `]}`, nil
+	case "enum":
+		symbols, _ := def["symbols"].([]interface{})
+		symbolStrings := make([]string, len(symbols))
+		for i, s := range symbols {
+			sym, _ := s.(string)
+			symbolStrings[i] = quoteJSONString(sym)
+		}
+		return `{"name":` + quoteJSONString(fullName(def)) + `,"type":"enum","symbols":[` + strings.Join(symbolStrings, ",") + `]}`, nil
+	case "fixed":
+		size, err := jsonNumberToInt64(def["size"])
+		if err != nil {
+			return "", fmt.Errorf("cannot canonicalize schema: fixed size: %s", err)
+		}
+		return fmt.Sprintf(`{"name":%s,"type":"fixed","size":%d}`, quoteJSONString(fullName(def)), size), nil
+	case "array":
+		items, err := canonicalString(def["items"])
+		if err != nil {
+			return "", err
+		}
+		return `{"type":"array","items":` + items + `}`, nil
+	case "map":
+		values, err := canonicalString(def["values"])
+		if err != nil {
+			return "", err
+		}
+		return `{"type":"map","values":` + values + `}`, nil
+	case "":
+		return "", fmt.Errorf("cannot canonicalize schema: missing or non-string \"type\" attribute")
+	default:
+		// A primitive named by a JSON object, possibly decorated with
+		// attributes irrelevant to parsing (e.g. "logicalType"). The
+		// canonical form of such a schema is simply its bare type name.
+		if !primitiveTypeNames[kind] {
+			return "", fmt.Errorf("cannot canonicalize schema: unknown type: %q", kind)
+		}
+		return quoteJSONString(kind), nil
+	}
+}
+
+// jsonNumberToInt64 converts a JSON decoded numeric attribute, which
+// encoding/json represents as float64, to an int64.
+func jsonNumberToInt64(v interface{}) (int64, error) {
+	f, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("expected number, received %T", v)
+	}
+	return int64(f), nil
+}
+
+// quoteJSONString renders s as a minimally escaped JSON string literal,
+// using encoding/json so the escaping rules match what every other piece of
+// this package expects a JSON string to look like.
+func quoteJSONString(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		// s came from a successfully unmarshaled JSON document, so it is
+		// always valid UTF-8 and always re-marshals without error.
+		panic(err)
+	}
+	return string(b)
+}
@@ -12,10 +12,32 @@ package goavro
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 )
 
-func ExampleCodecCanonicalSchema() {
+// ensureError checks err against the expected substrings in contains. With
+// no substrings given, it requires err to be nil; otherwise it requires err
+// to be non-nil and its message to contain every substring given.
+func ensureError(t *testing.T, err error, contains ...string) {
+	t.Helper()
+	if len(contains) == 0 {
+		if err != nil {
+			t.Fatalf("GOT: %v; WANT: %v", err, nil)
+		}
+		return
+	}
+	if err == nil {
+		t.Fatalf("GOT: %v; WANT: error containing %q", err, contains)
+	}
+	for _, c := range contains {
+		if !strings.Contains(err.Error(), c) {
+			t.Errorf("GOT: %v; WANT: error containing %q", err, c)
+		}
+	}
+}
+
+func ExampleCodec_CanonicalSchema() {
 	schema := `{"type":"map","values":{"type":"enum","name":"foo","symbols":["alpha","bravo"]}}`
 	codec, err := NewCodec(schema)
 	if err != nil {
@@ -0,0 +1,141 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+// confluentMagicByte is the leading byte of the Confluent Schema Registry
+// wire format. It always has the value 0.
+const confluentMagicByte = 0
+
+// confluentHeaderLength is the number of bytes in the Confluent wire format
+// header: one magic byte followed by a four byte big endian schema id.
+const confluentHeaderLength = 5
+
+// SchemaRegistry is satisfied by anything that can translate between an Avro
+// schema and the integer id a Confluent Schema Registry assigns to it. Users
+// are expected to supply an implementation backed by an HTTP client, an
+// in-memory cache, or a fake for use in tests.
+type SchemaRegistry interface {
+	// IDBySchema returns the schema id the registry has on file for the
+	// given schema, registering the schema if the registry does not
+	// already know about it.
+	IDBySchema(schema string) (int32, error)
+
+	// SchemaByID returns the schema text associated with id.
+	SchemaByID(id int32) (string, error)
+}
+
+// ConfluentFromNative appends the Confluent wire format encoding of datum to
+// buf and returns the resulting byte slice. The encoding is the single byte
+// 0x0, followed by the four byte big endian schemaID, followed by the Avro
+// binary encoding of datum. It is the caller's responsibility to supply the
+// schemaID the destination Schema Registry has on file for this codec's
+// schema, typically obtained from a SchemaRegistry.
+//
+// On error, it returns the original buf, not having appended any bytes to
+// it, with the one exception that it may have appended some bytes to buf
+// before discovering an encoding error, so in that case the actual bytes
+// appended ought to be ignored, and buf does not necessarily represent the
+// original buf provided by the caller.
+func (c *Codec) ConfluentFromNative(buf []byte, datum interface{}, schemaID int32) ([]byte, error) {
+	header := [confluentHeaderLength]byte{confluentMagicByte}
+	binary.BigEndian.PutUint32(header[1:], uint32(schemaID))
+	newBuf := append(buf, header[:]...)
+
+	newBuf, err := c.BinaryFromNative(newBuf, datum)
+	if err != nil {
+		return buf, err
+	}
+	return newBuf, nil
+}
+
+// NativeFromConfluent converts the provided buf from Confluent wire format
+// encoding to its native Go form, and also returns a byte slice of any
+// remaining bytes. It assumes the caller already resolved the codec to use
+// for decoding, typically by extracting the schema id with
+// SchemaIDFromConfluent and looking it up in a CodecRegistry.
+func (c *Codec) NativeFromConfluent(buf []byte) (interface{}, []byte, error) {
+	_, rest, err := SchemaIDFromConfluent(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+	return c.NativeFromBinary(rest)
+}
+
+// SchemaIDFromConfluent strips the Confluent wire format header from buf,
+// returning the schema id it identifies along with the remaining bytes,
+// which hold the Avro binary encoded datum.
+func SchemaIDFromConfluent(buf []byte) (int32, []byte, error) {
+	if len(buf) < confluentHeaderLength {
+		return 0, buf, fmt.Errorf("cannot decode confluent header: buffer underrun")
+	}
+	if buf[0] != confluentMagicByte {
+		return 0, buf, fmt.Errorf("cannot decode confluent header: expected magic byte 0x0; found: %#x", buf[0])
+	}
+	id := int32(binary.BigEndian.Uint32(buf[1:confluentHeaderLength]))
+	return id, buf[confluentHeaderLength:], nil
+}
+
+// CodecRegistry maintains a lookup of schema id to Codec, allowing a single
+// decoder to dispatch Confluent wire format encoded messages across however
+// many distinct schemas it encounters, such as when consuming many Avro
+// encoded topics from Kafka with a single consumer.
+type CodecRegistry struct {
+	mu     sync.RWMutex
+	codecs map[int32]*Codec
+}
+
+// NewCodecRegistry returns a new, empty CodecRegistry.
+func NewCodecRegistry() *CodecRegistry {
+	return &CodecRegistry{codecs: make(map[int32]*Codec)}
+}
+
+// Register associates schemaID with codec, so that a later call to
+// NativeFromConfluent for a message bearing that schemaID will use codec to
+// decode it.
+func (cr *CodecRegistry) Register(schemaID int32, codec *Codec) {
+	cr.mu.Lock()
+	cr.codecs[schemaID] = codec
+	cr.mu.Unlock()
+}
+
+// Codec returns the codec previously registered for schemaID, or an error if
+// no codec has been registered for it.
+func (cr *CodecRegistry) Codec(schemaID int32) (*Codec, error) {
+	cr.mu.RLock()
+	codec, ok := cr.codecs[schemaID]
+	cr.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("cannot find codec for schema id: %d", schemaID)
+	}
+	return codec, nil
+}
+
+// NativeFromConfluent extracts the schema id from the Confluent wire format
+// header in buf, looks up the corresponding codec, and uses it to decode the
+// remaining bytes. It returns the decoded native Go value, the schema id
+// that was used, and any remaining bytes following the datum.
+func (cr *CodecRegistry) NativeFromConfluent(buf []byte) (interface{}, int32, []byte, error) {
+	schemaID, rest, err := SchemaIDFromConfluent(buf)
+	if err != nil {
+		return nil, 0, buf, err
+	}
+	codec, err := cr.Codec(schemaID)
+	if err != nil {
+		return nil, schemaID, buf, err
+	}
+	datum, rest, err := codec.NativeFromBinary(rest)
+	return datum, schemaID, rest, err
+}
@@ -0,0 +1,160 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// fakeSchemaRegistry is a trivial in-memory SchemaRegistry used for tests.
+type fakeSchemaRegistry struct {
+	nextID      int32
+	schemasByID map[int32]string
+	idsBySchema map[string]int32
+}
+
+func newFakeSchemaRegistry() *fakeSchemaRegistry {
+	return &fakeSchemaRegistry{
+		schemasByID: make(map[int32]string),
+		idsBySchema: make(map[string]int32),
+	}
+}
+
+func (r *fakeSchemaRegistry) IDBySchema(schema string) (int32, error) {
+	if id, ok := r.idsBySchema[schema]; ok {
+		return id, nil
+	}
+	r.nextID++
+	r.idsBySchema[schema] = r.nextID
+	r.schemasByID[r.nextID] = schema
+	return r.nextID, nil
+}
+
+func (r *fakeSchemaRegistry) SchemaByID(id int32) (string, error) {
+	schema, ok := r.schemasByID[id]
+	if !ok {
+		return "", fmt.Errorf("cannot find schema for id: %d", id)
+	}
+	return schema, nil
+}
+
+func TestConfluentRoundTrip(t *testing.T) {
+	codec, err := NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := codec.ConfluentFromNative(nil, 42, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte{0x0, 0x0, 0x0, 0x0, 0x7, 0x54}
+	if got := buf; !bytes.Equal(got, want) {
+		t.Errorf("GOT: %#v; WANT: %#v", got, want)
+	}
+
+	datum, rest, err := codec.NativeFromConfluent(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := datum, int32(42); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := len(rest), 0; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestConfluentNativeFromConfluentShortBuffer(t *testing.T) {
+	codec, err := NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := codec.NativeFromConfluent([]byte{0x0, 0x1}); err == nil {
+		t.Fatal("expected error; got none")
+	}
+}
+
+func TestConfluentNativeFromConfluentBadMagicByte(t *testing.T) {
+	codec, err := NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x54}
+	if _, _, err := codec.NativeFromConfluent(buf); err == nil {
+		t.Fatal("expected error; got none")
+	}
+}
+
+func TestCodecRegistryDispatchesBySchemaID(t *testing.T) {
+	intCodec, err := NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringCodec, err := NewCodec(`"string"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := newFakeSchemaRegistry()
+	intID, err := registry.IDBySchema(intCodec.CanonicalSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringID, err := registry.IDBySchema(stringCodec.CanonicalSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cr := NewCodecRegistry()
+	cr.Register(intID, intCodec)
+	cr.Register(stringID, stringCodec)
+
+	intBuf, err := intCodec.ConfluentFromNative(nil, 13, intID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringBuf, err := stringCodec.ConfluentFromNative(nil, "hello", stringID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datum, gotID, _, err := cr.NativeFromConfluent(intBuf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := gotID, intID; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := datum, int32(13); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	datum, gotID, _, err = cr.NativeFromConfluent(stringBuf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := gotID, stringID; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := datum, "hello"; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestCodecRegistryUnknownSchemaID(t *testing.T) {
+	cr := NewCodecRegistry()
+	if _, err := cr.Codec(99); err == nil {
+		t.Fatal("expected error; got none")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+// crc64AvroEmpty is the seed the Avro specification defines for its
+// CRC-64-AVRO Rabin fingerprint: the fingerprint of the empty byte string.
+const crc64AvroEmpty uint64 = 0xc15d213aa4d7a795
+
+// crc64AvroTable is populated by init with the 256 entry lookup table the
+// Avro specification's reference algorithm derives from crc64AvroEmpty.
+var crc64AvroTable [256]uint64
+
+func init() {
+	for i := range crc64AvroTable {
+		fp := uint64(i)
+		for j := 0; j < 8; j++ {
+			if fp&1 == 1 {
+				fp = (fp >> 1) ^ crc64AvroEmpty
+			} else {
+				fp = fp >> 1
+			}
+		}
+		crc64AvroTable[i] = fp
+	}
+}
+
+// crc64Avro computes the Avro specification's CRC-64-AVRO Rabin fingerprint
+// of buf, intended to be called with a schema's parsing canonical form.
+func crc64Avro(buf []byte) uint64 {
+	fp := crc64AvroEmpty
+	for _, b := range buf {
+		fp = (fp >> 8) ^ crc64AvroTable[byte(fp)^b]
+	}
+	return fp
+}
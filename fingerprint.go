@@ -0,0 +1,182 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+)
+
+// singleObjectMagicBytes is the two byte marker that precedes every single
+// object encoded message, regardless of which fingerprinting algorithm
+// identifies the schema that follows it.
+var singleObjectMagicBytes = [2]byte{0xC3, 0x01}
+
+// The following constants enumerate the fingerprinting algorithms the Avro
+// specification recommends for identifying a schema by a digest of its
+// parsing canonical form. CRC-64-AVRO is the only algorithm the single
+// object encoding marker historically assumed; MD5 and SHA-256 are provided
+// for interop with other Avro implementations that key their single object
+// frames off one of those instead.
+const (
+	FingerprintCRC64Avro = "CRC-64-AVRO"
+	FingerprintMD5       = "MD5"
+	FingerprintSHA256    = "SHA-256"
+)
+
+// fingerprintSize returns the number of bytes algorithm produces, or an
+// error if algorithm is not recognized.
+func fingerprintSize(algorithm string) (int, error) {
+	switch algorithm {
+	case FingerprintCRC64Avro:
+		return 8, nil
+	case FingerprintMD5:
+		return 16, nil
+	case FingerprintSHA256:
+		return 32, nil
+	default:
+		return 0, fmt.Errorf("cannot fingerprint schema: unrecognized algorithm: %q", algorithm)
+	}
+}
+
+// Fingerprint returns the digest of the codec's schema parsing canonical
+// form, computed using the named algorithm. Supported algorithm values are
+// FingerprintCRC64Avro, FingerprintMD5, and FingerprintSHA256.
+func (c *Codec) Fingerprint(algorithm string) ([]byte, error) {
+	if _, err := fingerprintSize(algorithm); err != nil {
+		return nil, err
+	}
+
+	switch algorithm {
+	case FingerprintCRC64Avro:
+		fp := c.SchemaCRC64Avro()
+		buf := make([]byte, 8)
+		for i := 0; i < 8; i++ {
+			buf[i] = byte(fp)
+			fp >>= 8
+		}
+		return buf, nil
+	case FingerprintMD5:
+		sum := md5.Sum([]byte(c.CanonicalSchema()))
+		return sum[:], nil
+	case FingerprintSHA256:
+		sum := sha256.Sum256([]byte(c.CanonicalSchema()))
+		return sum[:], nil
+	}
+	panic("unreachable")
+}
+
+// SingleFromNativeFingerprint is identical to the unexported
+// singleFromNative except that it emits the fingerprint computed by the
+// named algorithm rather than always using the 8 byte CRC-64-AVRO Rabin
+// fingerprint, allowing interop with implementations that key their single
+// object frames off MD5 or SHA-256 instead.
+func (c *Codec) SingleFromNativeFingerprint(buf []byte, datum interface{}, algorithm string) ([]byte, error) {
+	fp, err := c.Fingerprint(algorithm)
+	if err != nil {
+		return buf, err
+	}
+
+	newBuf := append(buf, singleObjectMagicBytes[:]...)
+	newBuf = append(newBuf, fp...)
+
+	newBuf, err = c.BinaryFromNative(newBuf, datum)
+	if err != nil {
+		return buf, err
+	}
+	return newBuf, nil
+}
+
+// NativeFromSingleFingerprint is identical to the unexported
+// nativeFromSingle except that it expects the fingerprint following the
+// 0xC3 0x01 marker to have been produced by the named algorithm, and
+// therefore be fingerprintSize(algorithm) bytes long rather than always 8.
+func (c *Codec) NativeFromSingleFingerprint(buf []byte, algorithm string) (interface{}, []byte, error) {
+	size, err := fingerprintSize(algorithm)
+	if err != nil {
+		return nil, buf, err
+	}
+	if len(buf) < 2+size {
+		return nil, buf, fmt.Errorf("cannot decode binary single object encoding: buffer underrun")
+	}
+	if buf[0] != singleObjectMagicBytes[0] || buf[1] != singleObjectMagicBytes[1] {
+		return nil, buf, fmt.Errorf("cannot decode binary single object encoding: invalid marker")
+	}
+	want, err := c.Fingerprint(algorithm)
+	if err != nil {
+		return nil, buf, err
+	}
+	got := buf[2 : 2+size]
+	for i := range want {
+		if got[i] != want[i] {
+			return nil, buf, fmt.Errorf("cannot decode binary single object encoding: fingerprint mismatch")
+		}
+	}
+	return c.NativeFromBinary(buf[2+size:])
+}
+
+// FingerprintRegistry maps fingerprint digests, computed with any of
+// FingerprintCRC64Avro, FingerprintMD5, or FingerprintSHA256, to the Codec
+// that produced them, allowing a decoder to auto-detect which schema
+// produced an incoming single object encoded message without first knowing
+// which algorithm the producer used.
+type FingerprintRegistry struct {
+	mu     sync.RWMutex
+	codecs map[string]*Codec
+}
+
+// NewFingerprintRegistry returns a new, empty FingerprintRegistry.
+func NewFingerprintRegistry() *FingerprintRegistry {
+	return &FingerprintRegistry{codecs: make(map[string]*Codec)}
+}
+
+// Register computes codec's fingerprint under every supported algorithm and
+// adds each to the registry, so that a message bearing any of them can be
+// matched back to codec.
+func (fr *FingerprintRegistry) Register(codec *Codec) error {
+	for _, algorithm := range []string{FingerprintCRC64Avro, FingerprintMD5, FingerprintSHA256} {
+		fp, err := codec.Fingerprint(algorithm)
+		if err != nil {
+			return err
+		}
+		fr.mu.Lock()
+		fr.codecs[string(fp)] = codec
+		fr.mu.Unlock()
+	}
+	return nil
+}
+
+// NativeFromSingle decodes buf, which is expected to be single object
+// encoded using any of the algorithms known to FingerprintRegistry,
+// detecting which algorithm and schema produced it by trying each
+// fingerprint size in turn against the registered codecs. It returns the
+// decoded native Go value and any remaining bytes.
+func (fr *FingerprintRegistry) NativeFromSingle(buf []byte) (interface{}, []byte, error) {
+	if len(buf) < 2 || buf[0] != singleObjectMagicBytes[0] || buf[1] != singleObjectMagicBytes[1] {
+		return nil, buf, fmt.Errorf("cannot decode binary single object encoding: invalid marker")
+	}
+
+	for _, algorithm := range []string{FingerprintCRC64Avro, FingerprintMD5, FingerprintSHA256} {
+		size, _ := fingerprintSize(algorithm)
+		if len(buf) < 2+size {
+			continue
+		}
+		fr.mu.RLock()
+		codec, ok := fr.codecs[string(buf[2:2+size])]
+		fr.mu.RUnlock()
+		if !ok {
+			continue
+		}
+		return codec.NativeFromBinary(buf[2+size:])
+	}
+	return nil, buf, fmt.Errorf("cannot decode binary single object encoding: no registered schema matches fingerprint")
+}
@@ -0,0 +1,105 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import "testing"
+
+func TestCodecFingerprintSizes(t *testing.T) {
+	codec, err := NewCodec(`"string"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		Algorithm string
+		Size      int
+	}{
+		{FingerprintCRC64Avro, 8},
+		{FingerprintMD5, 16},
+		{FingerprintSHA256, 32},
+	}
+
+	for _, c := range cases {
+		fp, err := codec.Fingerprint(c.Algorithm)
+		if err != nil {
+			t.Fatalf("CASE: %s; %s", c.Algorithm, err)
+		}
+		if got, want := len(fp), c.Size; got != want {
+			t.Errorf("CASE: %s; GOT: %d; WANT: %d", c.Algorithm, got, want)
+		}
+	}
+}
+
+func TestCodecFingerprintUnknownAlgorithm(t *testing.T) {
+	codec, err := NewCodec(`"string"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := codec.Fingerprint("SHA-1"); err == nil {
+		t.Fatal("expected error; got none")
+	}
+}
+
+func TestSingleObjectEncodingWithFingerprintRoundTrip(t *testing.T) {
+	for _, algorithm := range []string{FingerprintCRC64Avro, FingerprintMD5, FingerprintSHA256} {
+		codec, err := NewCodec(`"long"`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf, err := codec.SingleFromNativeFingerprint(nil, int64(42), algorithm)
+		if err != nil {
+			t.Fatalf("CASE: %s; %s", algorithm, err)
+		}
+
+		datum, rest, err := codec.NativeFromSingleFingerprint(buf, algorithm)
+		if err != nil {
+			t.Fatalf("CASE: %s; %s", algorithm, err)
+		}
+		if got, want := datum, int64(42); got != want {
+			t.Errorf("CASE: %s; GOT: %v; WANT: %v", algorithm, got, want)
+		}
+		if got, want := len(rest), 0; got != want {
+			t.Errorf("CASE: %s; GOT: %v; WANT: %v", algorithm, got, want)
+		}
+	}
+}
+
+func TestFingerprintRegistryAutoDetectsAlgorithm(t *testing.T) {
+	intCodec, err := NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringCodec, err := NewCodec(`"string"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fr := NewFingerprintRegistry()
+	if err := fr.Register(intCodec); err != nil {
+		t.Fatal(err)
+	}
+	if err := fr.Register(stringCodec); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := intCodec.SingleFromNativeFingerprint(nil, int32(9), FingerprintSHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datum, _, err := fr.NativeFromSingle(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := datum, int32(9); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// ocfMagic is the four byte sequence that opens every Object Container File.
+var ocfMagic = [4]byte{'O', 'b', 'j', 1}
+
+// ocfLongCodec and ocfMetadataCodec encode/decode the two schemas the OCF
+// format itself is specified in terms of: the block framing longs, and the
+// file header's string-to-bytes metadata map.
+var ocfLongCodec = mustNewCodec(`"long"`)
+var ocfMetadataCodec = mustNewCodec(`{"type":"map","values":"bytes"}`)
+
+func mustNewCodec(schema string) *Codec {
+	codec, err := NewCodec(schema)
+	if err != nil {
+		panic(err)
+	}
+	return codec
+}
+
+// OCFConfig configures a new OCFWriter.
+type OCFConfig struct {
+	W io.Writer
+
+	// Codec describes the records to be written and is embedded in the
+	// file header so a reader can recover it without being told the
+	// schema out of band.
+	Codec *Codec
+
+	// CodecName selects the compression codec used for each data block.
+	// It defaults to CompressionNull.
+	CodecName CompressionCodec
+}
+
+// OCFWriter appends Avro records to an Object Container File.
+type OCFWriter struct {
+	w         io.Writer
+	codec     *Codec
+	codecName CompressionCodec
+	sync      [16]byte
+}
+
+// NewOCFWriter writes an OCF header built from config to config.W and
+// returns an OCFWriter ready to accept data blocks via Append.
+func NewOCFWriter(config OCFConfig) (*OCFWriter, error) {
+	if config.Codec == nil {
+		return nil, fmt.Errorf("cannot create OCFWriter: Codec is required")
+	}
+	codecName := config.CodecName
+	if codecName == "" {
+		codecName = CompressionNull
+	}
+	ocfw := &OCFWriter{w: config.W, codec: config.Codec, codecName: codecName}
+	if _, err := io.ReadFull(rand.Reader, ocfw.sync[:]); err != nil {
+		return nil, fmt.Errorf("cannot create OCFWriter: %s", err)
+	}
+	if err := ocfw.writeHeader(); err != nil {
+		return nil, err
+	}
+	return ocfw, nil
+}
+
+func (ocfw *OCFWriter) writeHeader() error {
+	meta := map[string]interface{}{
+		"avro.schema": []byte(ocfw.codec.Schema()),
+		"avro.codec":  []byte(ocfw.codecName),
+	}
+
+	buf := append([]byte{}, ocfMagic[:]...)
+	var err error
+	buf, err = ocfMetadataCodec.BinaryFromNative(buf, meta)
+	if err != nil {
+		return fmt.Errorf("cannot write OCF header: %s", err)
+	}
+	buf = append(buf, ocfw.sync[:]...)
+
+	_, err = ocfw.w.Write(buf)
+	return err
+}
+
+// Append binary encodes each value in data into a single new data block,
+// compresses the block using the codec named by OCFConfig.CodecName, and
+// writes it, framed per the OCF spec, to the underlying writer.
+func (ocfw *OCFWriter) Append(data []interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var block []byte
+	var err error
+	for _, datum := range data {
+		block, err = ocfw.codec.BinaryFromNative(block, datum)
+		if err != nil {
+			return fmt.Errorf("cannot encode OCF record: %s", err)
+		}
+	}
+
+	compressed, err := compressBlock(ocfw.codecName, block)
+	if err != nil {
+		return fmt.Errorf("cannot compress OCF block: %s", err)
+	}
+
+	buf, err := ocfLongCodec.BinaryFromNative(nil, int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("cannot encode OCF block count: %s", err)
+	}
+	buf, err = ocfLongCodec.BinaryFromNative(buf, int64(len(compressed)))
+	if err != nil {
+		return fmt.Errorf("cannot encode OCF block size: %s", err)
+	}
+	buf = append(buf, compressed...)
+	buf = append(buf, ocfw.sync[:]...)
+
+	_, err = ocfw.w.Write(buf)
+	return err
+}
+
+// OCFReader reads records from an Avro Object Container File, decompressing
+// each data block using the codec named in the file's own header.
+type OCFReader struct {
+	r         io.Reader
+	codec     *Codec
+	codecName CompressionCodec
+	sync      [16]byte
+
+	block     []byte
+	remaining int64
+	err       error
+}
+
+// NewOCFReader reads the OCF header from r, compiling the Codec described by
+// its embedded "avro.schema" metadata, and returns an OCFReader positioned
+// to read the file's first data block.
+func NewOCFReader(r io.Reader) (*OCFReader, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, fmt.Errorf("cannot read OCF header: %s", err)
+	}
+	if magic != ocfMagic {
+		return nil, fmt.Errorf("cannot read OCF header: not an OCF file: invalid magic bytes")
+	}
+
+	meta, err := readFraming(r, ocfMetadataCodec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read OCF header: %s", err)
+	}
+	metaMap, ok := meta.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot read OCF header: invalid metadata")
+	}
+
+	schemaBytes, ok := metaMap["avro.schema"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("cannot read OCF header: missing avro.schema metadata key")
+	}
+	codec, err := NewCodec(string(schemaBytes))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read OCF header: %s", err)
+	}
+
+	codecName := CompressionNull
+	if codecBytes, ok := metaMap["avro.codec"].([]byte); ok && len(codecBytes) > 0 {
+		codecName = CompressionCodec(codecBytes)
+	}
+
+	ocfr := &OCFReader{r: r, codec: codec, codecName: codecName}
+	if _, err := io.ReadFull(r, ocfr.sync[:]); err != nil {
+		return nil, fmt.Errorf("cannot read OCF header: %s", err)
+	}
+	return ocfr, nil
+}
+
+// Codec returns the Codec compiled from the schema embedded in the file
+// header.
+func (ocfr *OCFReader) Codec() *Codec { return ocfr.codec }
+
+// Err returns the error, if any, that Scan or Read recorded; once it is
+// non-nil, Scan returns false.
+func (ocfr *OCFReader) Err() error { return ocfr.err }
+
+// Scan reports whether a subsequent call to Read will succeed, reading and
+// decompressing the next data block from the underlying reader as needed.
+// Callers should check Err after Scan returns false.
+func (ocfr *OCFReader) Scan() bool {
+	if ocfr.err != nil {
+		return false
+	}
+	for ocfr.remaining == 0 {
+		if !ocfr.readBlock() {
+			return false
+		}
+	}
+	return true
+}
+
+func (ocfr *OCFReader) readBlock() bool {
+	countValue, err := readFraming(ocfr.r, ocfLongCodec)
+	if err != nil {
+		if err != io.EOF {
+			ocfr.err = fmt.Errorf("cannot read OCF block: %s", err)
+		}
+		return false
+	}
+	sizeValue, err := readFraming(ocfr.r, ocfLongCodec)
+	if err != nil {
+		ocfr.err = fmt.Errorf("cannot read OCF block: %s", err)
+		return false
+	}
+
+	compressed := make([]byte, sizeValue.(int64))
+	if _, err := io.ReadFull(ocfr.r, compressed); err != nil {
+		ocfr.err = fmt.Errorf("cannot read OCF block: %s", err)
+		return false
+	}
+
+	var sync [16]byte
+	if _, err := io.ReadFull(ocfr.r, sync[:]); err != nil {
+		ocfr.err = fmt.Errorf("cannot read OCF block: %s", err)
+		return false
+	}
+	if sync != ocfr.sync {
+		ocfr.err = fmt.Errorf("cannot read OCF block: sync marker mismatch")
+		return false
+	}
+
+	block, err := decompressBlock(ocfr.codecName, compressed)
+	if err != nil {
+		ocfr.err = fmt.Errorf("cannot read OCF block: %s", err)
+		return false
+	}
+
+	ocfr.block = block
+	ocfr.remaining = countValue.(int64)
+	return true
+}
+
+// Read decodes and returns the next record from the current data block.
+// Callers must call Scan, and check that it returned true, before every
+// call to Read.
+func (ocfr *OCFReader) Read() (interface{}, error) {
+	if ocfr.remaining == 0 {
+		return nil, fmt.Errorf("cannot read OCF record: no more records buffered; call Scan first")
+	}
+	datum, rest, err := ocfr.codec.NativeFromBinary(ocfr.block)
+	if err != nil {
+		ocfr.err = fmt.Errorf("cannot decode OCF record: %s", err)
+		return nil, ocfr.err
+	}
+	ocfr.block = rest
+	ocfr.remaining--
+	return datum, nil
+}
+
+// readFraming decodes a single value of the schema codec describes from r,
+// growing its read buffer one byte at a time until codec can decode it. It
+// is used for the handful of small, self-delimiting values the OCF format
+// itself is framed with (the header metadata map and the block count/size
+// longs), where the caller has no length prefix to read ahead of time.
+func readFraming(r io.Reader, codec *Codec) (interface{}, error) {
+	var buf []byte
+	one := make([]byte, 1)
+	for {
+		datum, _, err := codec.NativeFromBinary(buf)
+		if err == nil {
+			return datum, nil
+		}
+		if !isBufferUnderrun(err) {
+			return nil, err
+		}
+		n, rerr := r.Read(one)
+		if n == 1 {
+			buf = append(buf, one[0])
+			continue
+		}
+		if rerr != nil {
+			return nil, rerr
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// CompressionCodec identifies the block compression algorithm an OCF file
+// was written with. It is stored in the file header under the
+// "avro.codec" metadata key.
+type CompressionCodec string
+
+// The following CompressionCodec values are recognized by OCFReader and
+// OCFWriter and may be assigned to OCFConfig.CodecName.
+const (
+	CompressionNull      CompressionCodec = "null"
+	CompressionDeflate   CompressionCodec = "deflate"
+	CompressionSnappy    CompressionCodec = "snappy"
+	CompressionZStandard CompressionCodec = "zstandard"
+)
+
+// compressBlock compresses buf, the binary encoding of one OCF data block,
+// using the named codec, returning the bytes that ought to be written to
+// the file in place of buf.
+func compressBlock(codec CompressionCodec, buf []byte) ([]byte, error) {
+	switch codec {
+	case "", CompressionNull:
+		return buf, nil
+	case CompressionDeflate:
+		return compressDeflate(buf)
+	case CompressionSnappy:
+		return compressSnappy(buf)
+	case CompressionZStandard:
+		return compressZStandard(buf)
+	default:
+		return nil, fmt.Errorf("cannot compress block: unsupported codec: %q", codec)
+	}
+}
+
+// decompressBlock reverses compressBlock, returning the original block
+// bytes.
+func decompressBlock(codec CompressionCodec, buf []byte) ([]byte, error) {
+	switch codec {
+	case "", CompressionNull:
+		return buf, nil
+	case CompressionDeflate:
+		return decompressDeflate(buf)
+	case CompressionSnappy:
+		return decompressSnappy(buf)
+	case CompressionZStandard:
+		return decompressZStandard(buf)
+	default:
+		return nil, fmt.Errorf("cannot decompress block: unsupported codec: %q", codec)
+	}
+}
+
+// compressDeflate compresses buf using raw DEFLATE (no zlib or gzip
+// wrapper), matching the framing the Avro specification requires for the
+// "deflate" OCF codec.
+func compressDeflate(buf []byte) ([]byte, error) {
+	var out bytes.Buffer
+	w, err := flate.NewWriter(&out, flate.DefaultCompression)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compress deflate block: %s", err)
+	}
+	if _, err := w.Write(buf); err != nil {
+		return nil, fmt.Errorf("cannot compress deflate block: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("cannot compress deflate block: %s", err)
+	}
+	return out.Bytes(), nil
+}
+
+// decompressDeflate reverses compressDeflate.
+func decompressDeflate(buf []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(buf))
+	defer r.Close()
+
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress deflate block: %s", err)
+	}
+	return decompressed, nil
+}
+
+// compressSnappy compresses buf and appends the four byte big endian CRC-32
+// (IEEE) checksum of the uncompressed bytes, matching the framing the Avro
+// specification requires for the "snappy" OCF codec.
+func compressSnappy(buf []byte) ([]byte, error) {
+	compressed := snappy.Encode(nil, buf)
+	checksum := crc32.ChecksumIEEE(buf)
+
+	out := make([]byte, len(compressed)+4)
+	copy(out, compressed)
+	binary.BigEndian.PutUint32(out[len(compressed):], checksum)
+	return out, nil
+}
+
+// decompressSnappy reverses compressSnappy, verifying the trailing CRC-32
+// matches the checksum of the decompressed bytes.
+func decompressSnappy(buf []byte) ([]byte, error) {
+	if len(buf) < 4 {
+		return nil, fmt.Errorf("cannot decompress snappy block: buffer underrun")
+	}
+	compressed, trailer := buf[:len(buf)-4], buf[len(buf)-4:]
+
+	decompressed, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress snappy block: %s", err)
+	}
+
+	want := binary.BigEndian.Uint32(trailer)
+	if got := crc32.ChecksumIEEE(decompressed); got != want {
+		return nil, fmt.Errorf("cannot decompress snappy block: checksum mismatch: GOT: %#x; WANT: %#x", got, want)
+	}
+	return decompressed, nil
+}
+
+// compressZStandard compresses buf using the zstd default compression
+// level.
+func compressZStandard(buf []byte) ([]byte, error) {
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compress zstandard block: %s", err)
+	}
+	defer enc.Close()
+	return enc.EncodeAll(buf, nil), nil
+}
+
+// decompressZStandard stream-decodes a zstd compressed block.
+func decompressZStandard(buf []byte) ([]byte, error) {
+	dec, err := zstd.NewReader(bytes.NewReader(buf))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress zstandard block: %s", err)
+	}
+	defer dec.Close()
+
+	decompressed, err := io.ReadAll(dec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress zstandard block: %s", err)
+	}
+	return decompressed, nil
+}
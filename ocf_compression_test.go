@@ -0,0 +1,56 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOCFCompressionRoundTrip(t *testing.T) {
+	original := []byte("the quick brown fox jumps over the lazy dog, the quick brown fox jumps over the lazy dog")
+
+	for _, codec := range []CompressionCodec{CompressionNull, CompressionDeflate, CompressionSnappy, CompressionZStandard} {
+		compressed, err := compressBlock(codec, original)
+		if err != nil {
+			t.Fatalf("CASE: %s; %s", codec, err)
+		}
+		decompressed, err := decompressBlock(codec, compressed)
+		if err != nil {
+			t.Fatalf("CASE: %s; %s", codec, err)
+		}
+		if got, want := decompressed, original; !bytes.Equal(got, want) {
+			t.Errorf("CASE: %s; GOT: %q; WANT: %q", codec, got, want)
+		}
+	}
+}
+
+func TestOCFSnappyDetectsChecksumMismatch(t *testing.T) {
+	compressed, err := compressSnappy([]byte("hello world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Corrupt the trailing CRC-32.
+	compressed[len(compressed)-1] ^= 0xFF
+
+	if _, err := decompressSnappy(compressed); err == nil {
+		t.Fatal("expected checksum mismatch error; got none")
+	}
+}
+
+func TestOCFCompressionUnsupportedCodec(t *testing.T) {
+	if _, err := compressBlock("bzip2", []byte("hi")); err == nil {
+		t.Fatal("expected error; got none")
+	}
+	if _, err := decompressBlock("bzip2", []byte("hi")); err == nil {
+		t.Fatal("expected error; got none")
+	}
+}
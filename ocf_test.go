@@ -0,0 +1,63 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOCFRoundTripAcrossCompressionCodecs(t *testing.T) {
+	codec, err := NewCodec(`"string"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, codecName := range []CompressionCodec{CompressionNull, CompressionDeflate, CompressionSnappy, CompressionZStandard} {
+		var buf bytes.Buffer
+		ocfw, err := NewOCFWriter(OCFConfig{W: &buf, Codec: codec, CodecName: codecName})
+		if err != nil {
+			t.Fatalf("CASE: %s; %s", codecName, err)
+		}
+		if err := ocfw.Append([]interface{}{"alpha", "bravo"}); err != nil {
+			t.Fatalf("CASE: %s; %s", codecName, err)
+		}
+		if err := ocfw.Append([]interface{}{"charlie"}); err != nil {
+			t.Fatalf("CASE: %s; %s", codecName, err)
+		}
+
+		ocfr, err := NewOCFReader(&buf)
+		if err != nil {
+			t.Fatalf("CASE: %s; %s", codecName, err)
+		}
+
+		var got []interface{}
+		for ocfr.Scan() {
+			datum, err := ocfr.Read()
+			if err != nil {
+				t.Fatalf("CASE: %s; %s", codecName, err)
+			}
+			got = append(got, datum)
+		}
+		if err := ocfr.Err(); err != nil {
+			t.Fatalf("CASE: %s; %s", codecName, err)
+		}
+
+		want := []interface{}{"alpha", "bravo", "charlie"}
+		if len(got) != len(want) {
+			t.Fatalf("CASE: %s; GOT: %v; WANT: %v", codecName, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("CASE: %s; index %d; GOT: %v; WANT: %v", codecName, i, got[i], want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jsonableFromNative converts datum, a goavro native Go value shaped
+// according to avroNode, into a value safe to pass to encoding/json and on
+// to parquet-go's JSON writer: []byte becomes a string, and
+// ["null", T] unions are unwrapped to either nil or the bare T value.
+func jsonableFromNative(avroNode interface{}, datum interface{}) (interface{}, error) {
+	if branches, ok := avroNode.([]interface{}); ok {
+		inner, _, err := unwrapNullableUnion(branches)
+		if err != nil {
+			return nil, err
+		}
+		if datum == nil {
+			return nil, nil
+		}
+		m, ok := datum.(map[string]interface{})
+		if !ok || len(m) != 1 {
+			return nil, fmt.Errorf("cannot convert union value for parquet: unexpected shape %T", datum)
+		}
+		for _, v := range m {
+			return jsonableFromNative(inner, v)
+		}
+	}
+
+	switch v := datum.(type) {
+	case []byte:
+		return string(v), nil
+	case map[string]interface{}:
+		def, ok := avroNode.(map[string]interface{})
+		if !ok {
+			return v, nil
+		}
+		out := make(map[string]interface{}, len(v))
+		switch def["type"] {
+		case "record":
+			fields, _ := def["fields"].([]interface{})
+			for _, f := range fields {
+				fm, ok := f.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := fm["name"].(string)
+				cv, err := jsonableFromNative(fm["type"], v[name])
+				if err != nil {
+					return nil, err
+				}
+				out[name] = cv
+			}
+		case "map":
+			for k, val := range v {
+				cv, err := jsonableFromNative(def["values"], val)
+				if err != nil {
+					return nil, err
+				}
+				out[k] = cv
+			}
+		default:
+			return v, nil
+		}
+		return out, nil
+	case []interface{}:
+		def, ok := avroNode.(map[string]interface{})
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			var itemType interface{}
+			if ok {
+				itemType = def["items"]
+			}
+			cv, err := jsonableFromNative(itemType, item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = cv
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// avroTextFromParquet converts raw, one JSON row as github.com/xitongsys/
+// parquet-go's JSON reader decodes it, into the Avro textual encoding
+// codec.NativeFromTextual expects: record field names are remapped from
+// parquet-go's Go-exported struct field names back to their original Avro
+// spelling (see parquetFieldName), and nullable fields are re-wrapped into
+// Avro's {"branch":value} union form, reversing jsonableFromNative.
+func avroTextFromParquet(avroNode interface{}, raw json.RawMessage) (json.RawMessage, error) {
+	if branches, ok := avroNode.([]interface{}); ok {
+		inner, hasNull, err := unwrapNullableUnion(branches)
+		if err != nil {
+			return nil, err
+		}
+		if !hasNull {
+			return avroTextFromParquet(inner, raw)
+		}
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			return raw, nil
+		}
+		wrapped, err := avroTextFromParquet(inner, raw)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]json.RawMessage{avroUnionBranchName(inner): wrapped})
+	}
+
+	def, ok := avroNode.(map[string]interface{})
+	if !ok {
+		return raw, nil
+	}
+
+	switch def["type"] {
+	case "record":
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, fmt.Errorf("cannot convert parquet row for avro: %s", err)
+		}
+		fields, _ := def["fields"].([]interface{})
+		out := make(map[string]json.RawMessage, len(fields))
+		for _, f := range fields {
+			fm, ok := f.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := fm["name"].(string)
+			cv, err := avroTextFromParquet(fm["type"], m[parquetFieldName(name)])
+			if err != nil {
+				return nil, err
+			}
+			out[name] = cv
+		}
+		return json.Marshal(out)
+	case "map":
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, fmt.Errorf("cannot convert parquet row for avro: %s", err)
+		}
+		out := make(map[string]json.RawMessage, len(m))
+		for k, v := range m {
+			cv, err := avroTextFromParquet(def["values"], v)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = cv
+		}
+		return json.Marshal(out)
+	case "array":
+		var s []json.RawMessage
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, fmt.Errorf("cannot convert parquet row for avro: %s", err)
+		}
+		out := make([]json.RawMessage, len(s))
+		for i, item := range s {
+			cv, err := avroTextFromParquet(def["items"], item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = cv
+		}
+		return json.Marshal(out)
+	default:
+		return raw, nil
+	}
+}
+
+// parquetFieldName returns the Go-exported struct field name
+// github.com/xitongsys/parquet-go's JSON schema handler derives from an
+// Avro field name (see its common.StringToVariableName): the first letter
+// capitalized, the rest left unchanged.
+func parquetFieldName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// avroUnionBranchName returns the key Avro's JSON text encoding uses for a
+// union branch: a named type's fullname, or the bare type name otherwise,
+// mirroring this package's unionBranchName logic in the main goavro package.
+func avroUnionBranchName(node interface{}) string {
+	if name, ok := node.(string); ok {
+		return name
+	}
+	def, ok := node.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	t, _ := def["type"].(string)
+	switch t {
+	case "record", "enum", "fixed":
+		name, _ := def["name"].(string)
+		if ns, _ := def["namespace"].(string); ns != "" && !strings.ContainsRune(name, '.') {
+			return ns + "." + name
+		}
+		return name
+	default:
+		return t
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package parquet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJsonableFromNativeBytesBecomeString(t *testing.T) {
+	got, err := jsonableFromNative("bytes", []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "hello"; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestJsonableFromNativeUnwrapsNullableUnion(t *testing.T) {
+	avroNode := []interface{}{"null", "string"}
+
+	got, err := jsonableFromNative(avroNode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("GOT: %v; WANT: nil", got)
+	}
+
+	got, err = jsonableFromNative(avroNode, map[string]interface{}{"string": "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "hi"; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestJsonableFromNativeRecord(t *testing.T) {
+	avroNode := map[string]interface{}{
+		"type": "record",
+		"name": "Person",
+		"fields": []interface{}{
+			map[string]interface{}{"name": "name", "type": "string"},
+			map[string]interface{}{"name": "avatar", "type": "bytes"},
+		},
+	}
+	datum := map[string]interface{}{"name": "Alice", "avatar": []byte("png")}
+
+	got, err := jsonableFromNative(avroNode, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"name": "Alice", "avatar": "png"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestJsonableFromNativeMap(t *testing.T) {
+	avroNode := map[string]interface{}{"type": "map", "values": "bytes"}
+	datum := map[string]interface{}{"k": []byte("v")}
+
+	got, err := jsonableFromNative(avroNode, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"k": "v"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestJsonableFromNativeArray(t *testing.T) {
+	avroNode := map[string]interface{}{"type": "array", "items": "bytes"}
+	datum := []interface{}{[]byte("a"), []byte("b")}
+
+	got, err := jsonableFromNative(avroNode, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{"a", "b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestJsonableFromNativeRejectsMultiBranchUnion(t *testing.T) {
+	avroNode := []interface{}{"null", "int", "string"}
+
+	if _, err := jsonableFromNative(avroNode, map[string]interface{}{"int": int32(1)}); err == nil {
+		t.Fatal("expected error; got none")
+	}
+}
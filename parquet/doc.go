@@ -0,0 +1,37 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+// Package parquet bridges Avro Object Container Files and Parquet files,
+// translating a goavro Codec's schema into a github.com/xitongsys/parquet-go
+// schema and converting datum-by-datum between the two formats. It follows
+// the same Avro-to-Parquet type mapping conventions used by the JVM's
+// parquet-avro module:
+//
+//	Avro               Parquet
+//	boolean/int/long/  corresponding primitive, with repetition REQUIRED
+//	float/double/
+//	bytes/string
+//	decimal (bytes or  FIXED_LEN_BYTE_ARRAY/BYTE_ARRAY annotated DECIMAL
+//	fixed)
+//	date               INT32 annotated DATE
+//	time-millis        INT32 annotated TIME_MILLIS
+//	time-micros        INT64 annotated TIME_MICROS
+//	timestamp-millis   INT64 annotated TIMESTAMP_MILLIS
+//	timestamp-micros   INT64 annotated TIMESTAMP_MICROS
+//	uuid               BYTE_ARRAY annotated UTF8 (stored as its string form)
+//	record             group, one field per record field
+//	array              LIST group, one repeated "list" group holding "element"
+//	map                MAP group, one repeated key_value child with "key"/"value"
+//	["null", T]        the translation of T, marked OPTIONAL
+//
+// Unions with more than one non-null branch are not supported, as Parquet
+// has no native sum type; translate those schemas by hand before using this
+// package. A field whose type is a bare "null" is rejected, as it has no
+// storable column.
+package parquet
@@ -0,0 +1,136 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package parquet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	goavro "github.com/GannettDigital/linkedin_goavro"
+	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parallelism bounds the number of goroutines parquet-go uses internally
+// when marshaling rows. A modest, fixed value keeps this package's API
+// simple; callers converting very large files should use parquet-go
+// directly if they need to tune it.
+const parallelism = 4
+
+// ParquetFromOCF reads every record from ocf, an Avro Object Container File,
+// and writes it to pq as Parquet, deriving the Parquet schema from the OCF
+// file's own embedded Avro schema.
+func ParquetFromOCF(ocf io.Reader, pq io.Writer) error {
+	ocfReader, err := goavro.NewOCFReader(ocf)
+	if err != nil {
+		return fmt.Errorf("cannot read OCF: %s", err)
+	}
+
+	avroSchema := ocfReader.Codec().Schema()
+	pqSchema, err := schemaFromAvro(avroSchema)
+	if err != nil {
+		return fmt.Errorf("cannot derive parquet schema: %s", err)
+	}
+
+	var avroNode interface{}
+	if err := json.Unmarshal([]byte(avroSchema), &avroNode); err != nil {
+		return fmt.Errorf("cannot parse avro schema: %s", err)
+	}
+
+	pFile := writerfile.NewWriterFile(pq)
+	pqWriter, err := writer.NewJSONWriter(pqSchema, pFile, parallelism)
+	if err != nil {
+		return fmt.Errorf("cannot create parquet writer: %s", err)
+	}
+
+	for ocfReader.Scan() {
+		datum, err := ocfReader.Read()
+		if err != nil {
+			return fmt.Errorf("cannot read OCF record: %s", err)
+		}
+		jsonable, err := jsonableFromNative(avroNode, datum)
+		if err != nil {
+			return fmt.Errorf("cannot convert OCF record for parquet: %s", err)
+		}
+		row, err := json.Marshal(jsonable)
+		if err != nil {
+			return fmt.Errorf("cannot marshal record for parquet: %s", err)
+		}
+		if err := pqWriter.Write(string(row)); err != nil {
+			return fmt.Errorf("cannot write parquet record: %s", err)
+		}
+	}
+	if err := ocfReader.Err(); err != nil {
+		return fmt.Errorf("cannot read OCF: %s", err)
+	}
+
+	if err := pqWriter.WriteStop(); err != nil {
+		return fmt.Errorf("cannot finalize parquet file: %s", err)
+	}
+	return nil
+}
+
+// OCFFromParquet reads every row from pq, a Parquet file, and writes it to
+// ocf as an Avro Object Container File encoded with codec, whose schema
+// must describe the same fields as the Parquet file (see this package's
+// doc comment for the expected type mapping).
+func OCFFromParquet(pq source.ParquetFile, ocf io.Writer, codec *goavro.Codec) error {
+	pqSchema, err := schemaFromAvro(codec.Schema())
+	if err != nil {
+		return fmt.Errorf("cannot derive parquet schema: %s", err)
+	}
+
+	var avroNode interface{}
+	if err := json.Unmarshal([]byte(codec.Schema()), &avroNode); err != nil {
+		return fmt.Errorf("cannot parse avro schema: %s", err)
+	}
+
+	pqReader, err := reader.NewParquetReader(pq, pqSchema, parallelism)
+	if err != nil {
+		return fmt.Errorf("cannot create parquet reader: %s", err)
+	}
+	defer pqReader.ReadStop()
+
+	ocfWriter, err := goavro.NewOCFWriter(goavro.OCFConfig{W: ocf, Codec: codec})
+	if err != nil {
+		return fmt.Errorf("cannot create OCF writer: %s", err)
+	}
+
+	total := int(pqReader.GetNumRows())
+	for i := 0; i < total; i++ {
+		rows, err := pqReader.ReadByNumber(1)
+		if err != nil {
+			return fmt.Errorf("cannot read parquet row %d: %s", i, err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		row, err := json.Marshal(rows[0])
+		if err != nil {
+			return fmt.Errorf("cannot marshal parquet row %d: %s", i, err)
+		}
+		avroText, err := avroTextFromParquet(avroNode, row)
+		if err != nil {
+			return fmt.Errorf("cannot convert parquet row %d to avro: %s", i, err)
+		}
+		datum, _, err := codec.NativeFromTextual(avroText)
+		if err != nil {
+			return fmt.Errorf("cannot convert parquet row %d to avro: %s", i, err)
+		}
+		if err := ocfWriter.Append([]interface{}{datum}); err != nil {
+			return fmt.Errorf("cannot append OCF record %d: %s", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package parquet
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	goavro "github.com/GannettDigital/linkedin_goavro"
+	"github.com/xitongsys/parquet-go-source/buffer"
+)
+
+func TestOCFParquetRoundTrip(t *testing.T) {
+	schema := `{"type":"record","name":"Person","fields":[
+		{"name":"name","type":"string"},
+		{"name":"nickname","type":["null","string"],"default":null}
+	]}`
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ocfBuf bytes.Buffer
+	ocfWriter, err := goavro.NewOCFWriter(goavro.OCFConfig{W: &ocfBuf, Codec: codec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := []map[string]interface{}{
+		{"name": "Alice", "nickname": map[string]interface{}{"string": "Al"}},
+		{"name": "Bob", "nickname": nil},
+	}
+	for _, r := range records {
+		if err := ocfWriter.Append([]interface{}{r}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pqFile := buffer.NewBufferFile()
+	if err := ParquetFromOCF(&ocfBuf, pqFile); err != nil {
+		t.Fatalf("ParquetFromOCF: %s", err)
+	}
+
+	pqReadFile := buffer.NewBufferFileFromBytes(pqFile.Bytes())
+	var roundTripped bytes.Buffer
+	if err := OCFFromParquet(pqReadFile, &roundTripped, codec); err != nil {
+		t.Fatalf("OCFFromParquet: %s", err)
+	}
+
+	ocfReader, err := goavro.NewOCFReader(&roundTripped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]interface{}
+	for ocfReader.Scan() {
+		datum, err := ocfReader.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, datum.(map[string]interface{}))
+	}
+	if err := ocfReader.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []map[string]interface{}{
+		{"name": "Alice", "nickname": map[string]interface{}{"string": "Al"}},
+		{"name": "Bob", "nickname": nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %#v; WANT: %#v", got, want)
+	}
+}
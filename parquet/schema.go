@@ -0,0 +1,248 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package parquet
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jsonSchemaNode mirrors the shape github.com/xitongsys/parquet-go's
+// NewJSONWriter/NewJSONReader expect: a Tag string of comma separated
+// "key=value" pairs describing the node itself, plus nested Fields for
+// groups, lists, and maps.
+type jsonSchemaNode struct {
+	Tag    string            `json:"Tag"`
+	Fields []*jsonSchemaNode `json:"Fields,omitempty"`
+}
+
+// schemaFromAvro translates avroSchema, the JSON text of an Avro schema, into
+// the JSON schema text github.com/xitongsys/parquet-go's JSON reader/writer
+// expect, following the Avro/Parquet type mapping documented in this
+// package's doc comment.
+func schemaFromAvro(avroSchema string) (string, error) {
+	var node interface{}
+	if err := json.Unmarshal([]byte(avroSchema), &node); err != nil {
+		return "", fmt.Errorf("cannot translate schema: %s", err)
+	}
+
+	root, ok := asRecord(node)
+	if !ok {
+		return "", fmt.Errorf("cannot translate schema: top level schema must be a record")
+	}
+
+	schema, err := recordNode(root, "REQUIRED")
+	if err != nil {
+		return "", err
+	}
+
+	buf, err := json.Marshal(schema)
+	if err != nil {
+		return "", fmt.Errorf("cannot translate schema: %s", err)
+	}
+	return string(buf), nil
+}
+
+func asRecord(node interface{}) (map[string]interface{}, bool) {
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	if t, _ := m["type"].(string); t != "record" {
+		return nil, false
+	}
+	return m, true
+}
+
+// fieldNode translates a single Avro field's type (possibly ["null", T]) and
+// name into a jsonSchemaNode.
+func fieldNode(name string, typ interface{}) (*jsonSchemaNode, error) {
+	repetition := "REQUIRED"
+	if branches, ok := typ.([]interface{}); ok {
+		t, hasNull, err := unwrapNullableUnion(branches)
+		if err != nil {
+			return nil, err
+		}
+		typ = t
+		if hasNull {
+			repetition = "OPTIONAL"
+		}
+	}
+	return typedNode(name, typ, repetition)
+}
+
+// unwrapNullableUnion returns the single non-null branch of an
+// Avro ["null", T] union, reporting whether "null" was one of the branches.
+func unwrapNullableUnion(branches []interface{}) (interface{}, bool, error) {
+	var nonNull []interface{}
+	hasNull := false
+	for _, b := range branches {
+		if s, ok := b.(string); ok && s == "null" {
+			hasNull = true
+			continue
+		}
+		nonNull = append(nonNull, b)
+	}
+	if len(nonNull) != 1 {
+		return nil, false, fmt.Errorf("cannot translate schema: unions with more than one non-null branch are not supported")
+	}
+	return nonNull[0], hasNull, nil
+}
+
+func typedNode(name string, typ interface{}, repetition string) (*jsonSchemaNode, error) {
+	switch v := typ.(type) {
+	case string:
+		return primitiveNode(name, v, repetition)
+	case map[string]interface{}:
+		switch v["type"] {
+		case "record":
+			return recordNode(v, repetition, name)
+		case "array":
+			return listNode(name, v, repetition)
+		case "map":
+			return mapNode(name, v, repetition)
+		case "enum":
+			return &jsonSchemaNode{Tag: fmt.Sprintf("name=%s, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=%s", name, repetition)}, nil
+		case "fixed":
+			if v["logicalType"] == "decimal" {
+				return logicalNode(name, v, repetition)
+			}
+			size, _ := v["size"].(float64)
+			return &jsonSchemaNode{Tag: fmt.Sprintf("name=%s, type=FIXED_LEN_BYTE_ARRAY, length=%d, repetitiontype=%s", name, int(size), repetition)}, nil
+		default:
+			return logicalNode(name, v, repetition)
+		}
+	default:
+		return nil, fmt.Errorf("cannot translate schema: unsupported field type for %q: %T", name, typ)
+	}
+}
+
+func primitiveNode(name, avroType, repetition string) (*jsonSchemaNode, error) {
+	var tag string
+	switch avroType {
+	case "null":
+		return nil, fmt.Errorf("cannot translate schema: field %q: bare null type is not a storable column", name)
+	case "boolean":
+		tag = fmt.Sprintf("name=%s, type=BOOLEAN, repetitiontype=%s", name, repetition)
+	case "int":
+		tag = fmt.Sprintf("name=%s, type=INT32, repetitiontype=%s", name, repetition)
+	case "long":
+		tag = fmt.Sprintf("name=%s, type=INT64, repetitiontype=%s", name, repetition)
+	case "float":
+		tag = fmt.Sprintf("name=%s, type=FLOAT, repetitiontype=%s", name, repetition)
+	case "double":
+		tag = fmt.Sprintf("name=%s, type=DOUBLE, repetitiontype=%s", name, repetition)
+	case "bytes":
+		tag = fmt.Sprintf("name=%s, type=BYTE_ARRAY, repetitiontype=%s", name, repetition)
+	case "string":
+		tag = fmt.Sprintf("name=%s, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=%s", name, repetition)
+	default:
+		return nil, fmt.Errorf("cannot translate schema: field %q: unsupported primitive type %q", name, avroType)
+	}
+	return &jsonSchemaNode{Tag: tag}, nil
+}
+
+// logicalNode translates a schema carrying a "logicalType" attribute, such
+// as decimal, date, time-millis/micros, timestamp-millis/micros, and uuid.
+func logicalNode(name string, def map[string]interface{}, repetition string) (*jsonSchemaNode, error) {
+	logical, _ := def["logicalType"].(string)
+	base, _ := def["type"].(string)
+
+	switch logical {
+	case "decimal":
+		precision, _ := def["precision"].(float64)
+		scale, _ := def["scale"].(float64)
+		baseType := "BYTE_ARRAY"
+		if base == "fixed" {
+			size, _ := def["size"].(float64)
+			baseType = fmt.Sprintf("FIXED_LEN_BYTE_ARRAY, length=%d", int(size))
+		}
+		return &jsonSchemaNode{Tag: fmt.Sprintf("name=%s, type=%s, convertedtype=DECIMAL, precision=%d, scale=%d, repetitiontype=%s", name, baseType, int(precision), int(scale), repetition)}, nil
+	case "date":
+		return &jsonSchemaNode{Tag: fmt.Sprintf("name=%s, type=INT32, convertedtype=DATE, repetitiontype=%s", name, repetition)}, nil
+	case "time-millis":
+		return &jsonSchemaNode{Tag: fmt.Sprintf("name=%s, type=INT32, convertedtype=TIME_MILLIS, repetitiontype=%s", name, repetition)}, nil
+	case "time-micros":
+		return &jsonSchemaNode{Tag: fmt.Sprintf("name=%s, type=INT64, convertedtype=TIME_MICROS, repetitiontype=%s", name, repetition)}, nil
+	case "timestamp-millis":
+		return &jsonSchemaNode{Tag: fmt.Sprintf("name=%s, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=%s", name, repetition)}, nil
+	case "timestamp-micros":
+		return &jsonSchemaNode{Tag: fmt.Sprintf("name=%s, type=INT64, convertedtype=TIMESTAMP_MICROS, repetitiontype=%s", name, repetition)}, nil
+	case "uuid":
+		return &jsonSchemaNode{Tag: fmt.Sprintf("name=%s, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=%s", name, repetition)}, nil
+	default:
+		// No recognized logicalType: fall back to the underlying Avro type.
+		return primitiveNode(name, base, repetition)
+	}
+}
+
+func recordNode(def map[string]interface{}, repetition string, name ...string) (*jsonSchemaNode, error) {
+	fields, _ := def["fields"].([]interface{})
+
+	node := &jsonSchemaNode{}
+	if len(name) > 0 {
+		node.Tag = fmt.Sprintf("name=%s, repetitiontype=%s", name[0], repetition)
+	} else {
+		recordName, _ := def["name"].(string)
+		node.Tag = fmt.Sprintf("name=%s, repetitiontype=%s", strings.ToLower(recordName), repetition)
+	}
+
+	for _, f := range fields {
+		fm, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fname, _ := fm["name"].(string)
+		child, err := fieldNode(fname, fm["type"])
+		if err != nil {
+			return nil, err
+		}
+		node.Fields = append(node.Fields, child)
+	}
+	return node, nil
+}
+
+// listNode translates an Avro array into a Parquet LIST annotated group,
+// wrapping the item type in the "list"/"element" group nesting the Parquet
+// LogicalType spec requires.
+func listNode(name string, def map[string]interface{}, repetition string) (*jsonSchemaNode, error) {
+	element, err := typedNode("element", def["items"], "REQUIRED")
+	if err != nil {
+		return nil, err
+	}
+	list := &jsonSchemaNode{
+		Tag:    "name=list, repetitiontype=REPEATED",
+		Fields: []*jsonSchemaNode{element},
+	}
+	return &jsonSchemaNode{
+		Tag:    fmt.Sprintf("name=%s, type=LIST, repetitiontype=%s", name, repetition),
+		Fields: []*jsonSchemaNode{list},
+	}, nil
+}
+
+// mapNode translates an Avro map into a Parquet MAP annotated group, using
+// the "key_value"/"key"/"value" nesting the Parquet LogicalType spec
+// requires. Avro map keys are always strings.
+func mapNode(name string, def map[string]interface{}, repetition string) (*jsonSchemaNode, error) {
+	key := &jsonSchemaNode{Tag: "name=key, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"}
+	value, err := typedNode("value", def["values"], "REQUIRED")
+	if err != nil {
+		return nil, err
+	}
+	keyValue := &jsonSchemaNode{
+		Tag:    "name=key_value, repetitiontype=REPEATED",
+		Fields: []*jsonSchemaNode{key, value},
+	}
+	return &jsonSchemaNode{
+		Tag:    fmt.Sprintf("name=%s, type=MAP, repetitiontype=%s", name, repetition),
+		Fields: []*jsonSchemaNode{keyValue},
+	}, nil
+}
@@ -0,0 +1,93 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package parquet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchemaFromAvroPrimitiveFields(t *testing.T) {
+	schema := `{"type":"record","name":"Person","fields":[
+		{"name":"id","type":"long"},
+		{"name":"name","type":"string"},
+		{"name":"nickname","type":["null","string"],"default":null}
+	]}`
+
+	got, err := schemaFromAvro(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`name=id, type=INT64, repetitiontype=REQUIRED`,
+		`name=name, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED`,
+		`name=nickname, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected schema to contain %q; got: %s", want, got)
+		}
+	}
+}
+
+func TestSchemaFromAvroArrayBecomesList(t *testing.T) {
+	schema := `{"type":"record","name":"Team","fields":[
+		{"name":"members","type":{"type":"array","items":"string"}}
+	]}`
+
+	got, err := schemaFromAvro(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, `type=LIST`) {
+		t.Errorf("expected schema to describe a LIST; got: %s", got)
+	}
+}
+
+func TestSchemaFromAvroMapBecomesMap(t *testing.T) {
+	schema := `{"type":"record","name":"Config","fields":[
+		{"name":"tags","type":{"type":"map","values":"string"}}
+	]}`
+
+	got, err := schemaFromAvro(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, `type=MAP`) {
+		t.Errorf("expected schema to describe a MAP; got: %s", got)
+	}
+}
+
+func TestSchemaFromAvroRejectsMultiBranchUnion(t *testing.T) {
+	schema := `{"type":"record","name":"Rec","fields":[
+		{"name":"value","type":["null","int","string"]}
+	]}`
+
+	if _, err := schemaFromAvro(schema); err == nil {
+		t.Fatal("expected error; got none")
+	}
+}
+
+func TestSchemaFromAvroLogicalTypes(t *testing.T) {
+	schema := `{"type":"record","name":"Event","fields":[
+		{"name":"occurred_at","type":{"type":"long","logicalType":"timestamp-millis"}},
+		{"name":"amount","type":{"type":"bytes","logicalType":"decimal","precision":9,"scale":2}}
+	]}`
+
+	got, err := schemaFromAvro(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"convertedtype=TIMESTAMP_MILLIS", "convertedtype=DECIMAL"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected schema to contain %q; got: %s", want, got)
+		}
+	}
+}
@@ -0,0 +1,424 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ResolvingCodec decodes data encoded with one Avro schema, the writer
+// schema, into native Go values shaped according to a second, related Avro
+// schema, the reader schema, following the schema resolution rules from the
+// Avro specification. It is obtained from NewResolvingDecoder or
+// Codec.ResolveWith.
+//
+// ResolvingCodec supports promoted numeric types (int to long/float/double,
+// long to float/double, float to double, string to/from bytes), record
+// field addition using the reader's default value, record field removal,
+// record field reordering by name, enum symbol reordering together with the
+// enum default when the writer's symbol is unknown to the reader, union
+// branch reordering, and matching fields, records, and enums via their
+// "aliases". It does not resolve array item type changes beyond the rules
+// above, nor does it follow schema references across separately compiled
+// codecs.
+type ResolvingCodec struct {
+	writer *Codec
+	reader *Codec
+
+	writerNode interface{}
+	readerNode interface{}
+}
+
+// NewResolvingDecoder compiles a ResolvingCodec that reads data encoded
+// using writerSchema and yields native Go values shaped according to
+// readerSchema.
+func NewResolvingDecoder(writerSchema, readerSchema string) (*ResolvingCodec, error) {
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve schema: writer schema: %s", err)
+	}
+	reader, err := NewCodec(readerSchema)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve schema: reader schema: %s", err)
+	}
+	return newResolvingCodec(writer, reader, writerSchema, readerSchema)
+}
+
+// ResolveWith compiles a ResolvingCodec that reads data encoded using c as
+// the writer schema and yields native Go values shaped according to reader.
+func (c *Codec) ResolveWith(reader *Codec) (*ResolvingCodec, error) {
+	return newResolvingCodec(c, reader, c.Schema(), reader.Schema())
+}
+
+func newResolvingCodec(writer, reader *Codec, writerSchema, readerSchema string) (*ResolvingCodec, error) {
+	var writerNode, readerNode interface{}
+	if err := json.Unmarshal([]byte(writerSchema), &writerNode); err != nil {
+		return nil, fmt.Errorf("cannot resolve schema: writer schema: %s", err)
+	}
+	if err := json.Unmarshal([]byte(readerSchema), &readerNode); err != nil {
+		return nil, fmt.Errorf("cannot resolve schema: reader schema: %s", err)
+	}
+	return &ResolvingCodec{writer: writer, reader: reader, writerNode: writerNode, readerNode: readerNode}, nil
+}
+
+// NativeFromBinary decodes buf, which is expected to have been encoded using
+// the writer schema, and returns the resulting value resolved into the
+// shape of the reader schema, along with any remaining bytes.
+func (rc *ResolvingCodec) NativeFromBinary(buf []byte) (interface{}, []byte, error) {
+	datum, rest, err := rc.writer.NativeFromBinary(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+	resolved, err := resolveValue(rc.writerNode, rc.readerNode, datum, rc.writer.namedTypes, rc.reader.namedTypes)
+	if err != nil {
+		return nil, rest, err
+	}
+	return resolved, rest, nil
+}
+
+// aliasesOf returns the string aliases listed on node, if any.
+func aliasesOf(def map[string]interface{}) []string {
+	raw, ok := def["aliases"].([]interface{})
+	if !ok {
+		return nil
+	}
+	aliases := make([]string, 0, len(raw))
+	for _, a := range raw {
+		if s, ok := a.(string); ok {
+			aliases = append(aliases, s)
+		}
+	}
+	return aliases
+}
+
+// namesMatch reports whether a field, record, or enum named writerName
+// (with the given writer aliases) ought to bind to one named readerName.
+func namesMatch(writerName string, writerAliases []string, readerName string) bool {
+	if writerName == readerName {
+		return true
+	}
+	for _, a := range writerAliases {
+		if a == readerName {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveValue(writerNode, readerNode, datum interface{}, writerRegistry, readerRegistry map[string]interface{}) (interface{}, error) {
+	writerNode = resolveNamedNode(writerNode, writerRegistry)
+	readerNode = resolveNamedNode(readerNode, readerRegistry)
+
+	writerKind, writerDef := schemaKind(writerNode)
+	readerKind, readerDef := schemaKind(readerNode)
+
+	if readerKind == "union" {
+		if writerKind == "union" {
+			writerBranch, err := pickUnionBranch(writerNode, datum)
+			if err != nil {
+				return nil, err
+			}
+			return resolveIntoUnion(writerBranch, readerNode, unwrapUnion(datum), writerRegistry, readerRegistry)
+		}
+		return resolveIntoUnion(writerNode, readerNode, datum, writerRegistry, readerRegistry)
+	}
+
+	if writerKind == "union" {
+		writerBranch, err := pickUnionBranch(writerNode, datum)
+		if err != nil {
+			return nil, err
+		}
+		return resolveValue(writerBranch, readerNode, unwrapUnion(datum), writerRegistry, readerRegistry)
+	}
+
+	switch writerKind {
+	case "record":
+		return resolveRecord(writerDef, readerDef, datum, writerRegistry, readerRegistry)
+	case "enum":
+		return resolveEnum(writerDef, readerDef, datum)
+	case "array":
+		return resolveArray(writerDef, readerDef, datum, writerRegistry, readerRegistry)
+	case "map":
+		return resolveMap(writerDef, readerDef, datum, writerRegistry, readerRegistry)
+	case "fixed":
+		if readerKind != "fixed" || !namedTypesMatch(writerDef, readerDef) {
+			return nil, fmt.Errorf("cannot resolve schema: writer fixed %q does not match reader %q", fullName(writerDef), unionBranchName(readerNode))
+		}
+		return datum, nil
+	default:
+		return resolvePrimitive(writerKind, readerKind, datum)
+	}
+}
+
+// unwrapUnion returns the single value held by datum's goavro union
+// representation (map[string]interface{}{branch: value}), or nil as-is for
+// a null branch.
+func unwrapUnion(datum interface{}) interface{} {
+	m, ok := datum.(map[string]interface{})
+	if !ok {
+		return datum
+	}
+	for _, v := range m {
+		return v
+	}
+	return nil
+}
+
+// pickUnionBranch returns the writer union member schema node that produced
+// datum, identified by the single key of datum's map representation, or
+// "null" when datum is nil. It returns an error, rather than guessing, when
+// datum's shape does not identify exactly one of unionNode's branches.
+func pickUnionBranch(unionNode interface{}, datum interface{}) (interface{}, error) {
+	branches, _ := unionNode.([]interface{})
+	if datum == nil {
+		return "null", nil
+	}
+	m, ok := datum.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return nil, fmt.Errorf("cannot resolve schema: writer union value has unexpected shape: %T", datum)
+	}
+	var branchName string
+	for k := range m {
+		branchName = k
+	}
+	for _, b := range branches {
+		if unionBranchName(b) == branchName {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot resolve schema: no writer union branch named %q", branchName)
+}
+
+// resolveIntoUnion resolves datum, shaped according to writerNode, into one
+// of readerNode's branches, trying each in turn and keeping the first that
+// both resolves without error and, for a record, enum, or fixed branch,
+// identifies the same named type as writerNode (see resolveRecord and
+// resolveEnum); a structurally compatible but differently-named candidate is
+// rejected rather than silently accepted. The result is re-wrapped in
+// goavro's union native representation.
+func resolveIntoUnion(writerNode, readerNode interface{}, datum interface{}, writerRegistry, readerRegistry map[string]interface{}) (interface{}, error) {
+	if datum == nil {
+		return nil, nil
+	}
+	branches, _ := readerNode.([]interface{})
+	for _, b := range branches {
+		kind, _ := schemaKind(b)
+		if kind == "null" {
+			continue
+		}
+		name := unionBranchName(b)
+		value, err := resolveValue(writerNode, b, datum, writerRegistry, readerRegistry)
+		if err != nil {
+			continue
+		}
+		return map[string]interface{}{name: value}, nil
+	}
+	return nil, fmt.Errorf("cannot resolve schema: reader union has no compatible branch")
+}
+
+func resolvePrimitive(writerKind, readerKind string, datum interface{}) (interface{}, error) {
+	if writerKind == readerKind {
+		return datum, nil
+	}
+	switch writerKind {
+	case "int":
+		v, ok := datum.(int32)
+		if !ok {
+			break
+		}
+		switch readerKind {
+		case "long":
+			return int64(v), nil
+		case "float":
+			return float32(v), nil
+		case "double":
+			return float64(v), nil
+		}
+	case "long":
+		v, ok := datum.(int64)
+		if !ok {
+			break
+		}
+		switch readerKind {
+		case "float":
+			return float32(v), nil
+		case "double":
+			return float64(v), nil
+		}
+	case "float":
+		v, ok := datum.(float32)
+		if !ok {
+			break
+		}
+		if readerKind == "double" {
+			return float64(v), nil
+		}
+	case "string":
+		if readerKind == "bytes" {
+			if v, ok := datum.(string); ok {
+				return []byte(v), nil
+			}
+		}
+	case "bytes":
+		if readerKind == "string" {
+			if v, ok := datum.([]byte); ok {
+				return string(v), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("cannot resolve schema: cannot promote %s to %s", writerKind, readerKind)
+}
+
+func resolveArray(writerDef, readerDef map[string]interface{}, datum interface{}, writerRegistry, readerRegistry map[string]interface{}) (interface{}, error) {
+	items, ok := datum.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot resolve schema: expected []interface{} for array; received %T", datum)
+	}
+	resolved := make([]interface{}, len(items))
+	for i, item := range items {
+		v, err := resolveValue(writerDef["items"], readerDef["items"], item, writerRegistry, readerRegistry)
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = v
+	}
+	return resolved, nil
+}
+
+func resolveMap(writerDef, readerDef map[string]interface{}, datum interface{}, writerRegistry, readerRegistry map[string]interface{}) (interface{}, error) {
+	values, ok := datum.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot resolve schema: expected map[string]interface{} for map; received %T", datum)
+	}
+	resolved := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		rv, err := resolveValue(writerDef["values"], readerDef["values"], v, writerRegistry, readerRegistry)
+		if err != nil {
+			return nil, err
+		}
+		resolved[k] = rv
+	}
+	return resolved, nil
+}
+
+// namedTypesMatch reports whether writerDef and readerDef -- both record
+// definitions or both enum definitions -- identify the same named type:
+// either they share a full name, or readerDef lists writerDef's full name
+// among its own aliases. Two named types that fail this check must not be
+// resolved against one another, even when one happens to satisfy the
+// other's structural rules (e.g. two records with the same field names).
+func namedTypesMatch(writerDef, readerDef map[string]interface{}) bool {
+	writerName, readerName := fullName(writerDef), fullName(readerDef)
+	return namesMatch(writerName, nil, readerName) || namesMatch(readerName, aliasesOf(readerDef), writerName)
+}
+
+func resolveEnum(writerDef, readerDef map[string]interface{}, datum interface{}) (interface{}, error) {
+	if !namedTypesMatch(writerDef, readerDef) {
+		return nil, fmt.Errorf("cannot resolve schema: writer enum %q does not match reader enum %q", fullName(writerDef), fullName(readerDef))
+	}
+
+	symbol, ok := datum.(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot resolve schema: expected string for enum; received %T", datum)
+	}
+	readerSymbols, _ := readerDef["symbols"].([]interface{})
+	for _, s := range readerSymbols {
+		if s == symbol {
+			return symbol, nil
+		}
+	}
+	if def, ok := readerDef["default"].(string); ok {
+		return def, nil
+	}
+	return nil, fmt.Errorf("cannot resolve schema: enum symbol %q unknown to reader and reader enum defines no default", symbol)
+}
+
+func resolveRecord(writerDef, readerDef map[string]interface{}, datum interface{}, writerRegistry, readerRegistry map[string]interface{}) (interface{}, error) {
+	if !namedTypesMatch(writerDef, readerDef) {
+		return nil, fmt.Errorf("cannot resolve schema: writer record %q does not match reader record %q", fullName(writerDef), fullName(readerDef))
+	}
+
+	values, ok := datum.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot resolve schema: expected map[string]interface{} for record; received %T", datum)
+	}
+
+	writerFields, _ := writerDef["fields"].([]interface{})
+	readerFields, _ := readerDef["fields"].([]interface{})
+
+	matched := make(map[string]bool, len(readerFields))
+	resolved := make(map[string]interface{}, len(readerFields))
+
+	for _, wf := range writerFields {
+		wfm, ok := wf.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		wname, _ := wfm["name"].(string)
+		value, ok := values[wname]
+		if !ok {
+			continue
+		}
+
+		rfm := findMatchingField(wname, readerFields)
+		if rfm == nil {
+			// Field present in writer but absent from reader: skip it.
+			continue
+		}
+		rname, _ := rfm["name"].(string)
+
+		rv, err := resolveValue(wfm["type"], rfm["type"], value, writerRegistry, readerRegistry)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve schema: field %q: %s", wname, err)
+		}
+		resolved[rname] = rv
+		matched[rname] = true
+	}
+
+	for _, rf := range readerFields {
+		rfm, ok := rf.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rname, _ := rfm["name"].(string)
+		if matched[rname] {
+			continue
+		}
+		def, ok := rfm["default"]
+		if !ok {
+			return nil, fmt.Errorf("cannot resolve schema: field %q added by reader has no default value", rname)
+		}
+		rv, err := nativeFromDefault(rfm["type"], def, readerRegistry)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve schema: field %q default: %s", rname, err)
+		}
+		resolved[rname] = rv
+	}
+
+	return resolved, nil
+}
+
+// findMatchingField returns the reader field definition that binds to a
+// writer field named writerName, matching on the reader field's own name or
+// any of its aliases.
+func findMatchingField(writerName string, readerFields []interface{}) map[string]interface{} {
+	for _, rf := range readerFields {
+		rfm, ok := rf.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rname, _ := rfm["name"].(string)
+		if namesMatch(writerName, nil, rname) || namesMatch(rname, aliasesOf(rfm), writerName) {
+			return rfm
+		}
+	}
+	return nil
+}
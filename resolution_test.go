@@ -0,0 +1,318 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolvingDecoderPromotesIntToLong(t *testing.T) {
+	writer, err := NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := NewResolvingDecoder(`"int"`, `"long"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := writer.BinaryFromNative(nil, int32(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datum, _, err := rc.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := datum, int64(42); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestResolvingDecoderFillsAddedFieldFromDefault(t *testing.T) {
+	writerSchema := `{"type":"record","name":"Rec","fields":[{"name":"a","type":"int"}]}`
+	readerSchema := `{"type":"record","name":"Rec","fields":[{"name":"a","type":"int"},{"name":"b","type":"string","default":"unset"}]}`
+
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := NewResolvingDecoder(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := writer.BinaryFromNative(nil, map[string]interface{}{"a": int32(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datum, _, err := rc.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"a": int32(1), "b": "unset"}
+	if got := datum; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestResolvingDecoderSkipsRemovedField(t *testing.T) {
+	writerSchema := `{"type":"record","name":"Rec","fields":[{"name":"a","type":"int"},{"name":"b","type":"string"}]}`
+	readerSchema := `{"type":"record","name":"Rec","fields":[{"name":"a","type":"int"}]}`
+
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := NewResolvingDecoder(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := writer.BinaryFromNative(nil, map[string]interface{}{"a": int32(1), "b": "dropped"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datum, _, err := rc.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"a": int32(1)}
+	if got := datum; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestResolvingDecoderEnumDefaultForUnknownSymbol(t *testing.T) {
+	writerSchema := `{"type":"enum","name":"Suit","symbols":["HEARTS","SPADES"]}`
+	readerSchema := `{"type":"enum","name":"Suit","symbols":["HEARTS"],"default":"HEARTS"}`
+
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := NewResolvingDecoder(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := writer.BinaryFromNative(nil, "SPADES")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datum, _, err := rc.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := datum, "HEARTS"; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestResolvingDecoderNullableRecordUnion(t *testing.T) {
+	innerSchema := `{"type":"record","name":"Address","fields":[{"name":"city","type":"string"}]}`
+	writerSchema := `{"type":"record","name":"Person","fields":[
+		{"name":"name","type":"string"},
+		{"name":"address","type":["null",` + innerSchema + `]}
+	]}`
+	readerSchema := writerSchema
+
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := NewResolvingDecoder(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datum := map[string]interface{}{
+		"name": "Alice",
+		"address": map[string]interface{}{
+			"Address": map[string]interface{}{"city": "Springfield"},
+		},
+	}
+	buf, err := writer.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, _, err := rc.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resolved, datum; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestResolvingDecoderUnionRejectsRecordWithUnrelatedName(t *testing.T) {
+	writerSchema := `{"type":"record","name":"A","fields":[{"name":"x","type":"int"}]}`
+	readerSchema := `["null",
+		{"type":"record","name":"B","fields":[{"name":"x","type":"int"}]},
+		{"type":"record","name":"C","fields":[{"name":"x","type":"int"},{"name":"y","type":"int","default":0}]}
+	]`
+
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := NewResolvingDecoder(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := writer.BinaryFromNative(nil, map[string]interface{}{"x": int32(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := rc.NativeFromBinary(buf); err == nil {
+		t.Fatal("expected error; got none")
+	}
+}
+
+func TestResolvingDecoderUnionMatchesRecordByAlias(t *testing.T) {
+	writerSchema := `{"type":"record","name":"A","fields":[{"name":"x","type":"int"}]}`
+	readerSchema := `["null",{"type":"record","name":"B","aliases":["A"],"fields":[{"name":"x","type":"int"}]}]`
+
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := NewResolvingDecoder(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := writer.BinaryFromNative(nil, map[string]interface{}{"x": int32(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datum, _, err := rc.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"B": map[string]interface{}{"x": int32(1)}}
+	if got := datum; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestResolvingDecoderFillsAddedIntFieldFromDefault(t *testing.T) {
+	writerSchema := `{"type":"record","name":"Rec","fields":[{"name":"a","type":"int"}]}`
+	readerSchema := `{"type":"record","name":"Rec","fields":[{"name":"a","type":"int"},{"name":"b","type":"int","default":5}]}`
+
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := NewResolvingDecoder(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := writer.BinaryFromNative(nil, map[string]interface{}{"a": int32(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datum, _, err := rc.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"a": int32(1), "b": int32(5)}
+	if got := datum; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	reader, err := NewCodec(readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reader.BinaryFromNative(nil, datum); err != nil {
+		t.Fatalf("resolved default did not re-encode as native int: %s", err)
+	}
+}
+
+func TestResolvingDecoderResolvesFieldReachedByNamedReference(t *testing.T) {
+	writerSchema := `{"type":"record","name":"Outer","fields":[
+		{"name":"inline","type":{"type":"record","name":"Inner","fields":[{"name":"x","type":"int"}]}},
+		{"name":"ref","type":"Inner"}
+	]}`
+	readerSchema := `{"type":"record","name":"Outer","fields":[
+		{"name":"inline","type":{"type":"record","name":"Inner","fields":[{"name":"x","type":"long"}]}},
+		{"name":"ref","type":"Inner"}
+	]}`
+
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := NewResolvingDecoder(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datum := map[string]interface{}{
+		"inline": map[string]interface{}{"x": int32(1)},
+		"ref":    map[string]interface{}{"x": int32(2)},
+	}
+	buf, err := writer.BinaryFromNative(nil, datum)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, _, err := rc.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"inline": map[string]interface{}{"x": int64(1)},
+		"ref":    map[string]interface{}{"x": int64(2)},
+	}
+	if got := resolved; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestResolvingDecoderUnionBranchReordering(t *testing.T) {
+	writerSchema := `["null","string","int"]`
+	readerSchema := `["null","int","string"]`
+
+	writer, err := NewCodec(writerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := NewResolvingDecoder(writerSchema, readerSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := writer.BinaryFromNative(nil, map[string]interface{}{"int": int32(7)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datum, _, err := rc.NativeFromBinary(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"int": int32(7)}
+	if got := datum; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
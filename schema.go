@@ -0,0 +1,217 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+// Package goavro implements encoding and decoding of Avro data, converting
+// between a schema's Avro binary or Avro JSON textual encoding and native Go
+// values.
+//
+// Every function that accepts or returns a native Go value shapes it the
+// same way: null maps to nil; boolean to bool; int to int32; long to int64;
+// float to float32; double to float64; bytes and fixed to []byte; string
+// and enum to string; array to []interface{}; map and record to
+// map[string]interface{}; and union to either nil, for the "null" branch,
+// or a single-key map[string]interface{} keyed by the branch's name (its
+// namespace-qualified name for a record, enum, or fixed; its type keyword
+// otherwise) holding the branch's own native value.
+package goavro
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Codec supports decoding and encoding of a single Avro schema, in both its
+// binary and textual forms, obtained by calling NewCodec.
+type Codec struct {
+	schemaOriginal  string
+	schemaCanonical string
+	node            interface{}
+	namedTypes      map[string]interface{}
+}
+
+// NewCodec parses schema, an Avro schema specified as JSON, and returns a
+// Codec that can encode and decode data described by it.
+func NewCodec(schema string) (*Codec, error) {
+	var node interface{}
+	if err := json.Unmarshal([]byte(schema), &node); err != nil {
+		return nil, fmt.Errorf("cannot parse schema: %s", err)
+	}
+
+	canonical, err := canonicalString(node)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse schema: %s", err)
+	}
+
+	namedTypes := make(map[string]interface{})
+	registerNamedTypes(node, namedTypes)
+
+	return &Codec{
+		schemaOriginal:  schema,
+		schemaCanonical: canonical,
+		node:            node,
+		namedTypes:      namedTypes,
+	}, nil
+}
+
+// registerNamedTypes walks node, recording every record, enum, and fixed
+// definition it finds into registry, keyed by its namespace-qualified name,
+// so later bare references to that name (including a record field that
+// refers back to its own enclosing record) can be resolved back to the
+// definition that named it.
+func registerNamedTypes(node interface{}, registry map[string]interface{}) {
+	switch v := node.(type) {
+	case []interface{}:
+		for _, branch := range v {
+			registerNamedTypes(branch, registry)
+		}
+	case map[string]interface{}:
+		kind, _ := v["type"].(string)
+		switch kind {
+		case "record":
+			registry[fullName(v)] = v
+			fields, _ := v["fields"].([]interface{})
+			for _, f := range fields {
+				if fm, ok := f.(map[string]interface{}); ok {
+					registerNamedTypes(fm["type"], registry)
+				}
+			}
+		case "enum", "fixed":
+			registry[fullName(v)] = v
+		case "array":
+			registerNamedTypes(v["items"], registry)
+		case "map":
+			registerNamedTypes(v["values"], registry)
+		}
+	}
+}
+
+// resolveNamedNode returns the registered definition for node when node is a
+// bare reference to a previously defined record, enum, or fixed type, and
+// node unchanged otherwise.
+func resolveNamedNode(node interface{}, registry map[string]interface{}) interface{} {
+	name, ok := node.(string)
+	if !ok || primitiveTypeNames[name] {
+		return node
+	}
+	if def, ok := registry[name]; ok {
+		return def
+	}
+	return node
+}
+
+// schemaKind normalizes node, the JSON decoded representation of an Avro
+// schema, into its base type kind ("record", "enum", "fixed", "array",
+// "map", "union", or a primitive name) along with the map describing it, if
+// it has one.
+func schemaKind(node interface{}) (string, map[string]interface{}) {
+	switch v := node.(type) {
+	case string:
+		return v, nil
+	case []interface{}:
+		return "union", nil
+	case map[string]interface{}:
+		if t, ok := v["type"]; ok {
+			if kind, ok := t.(string); ok {
+				switch kind {
+				case "record", "enum", "fixed", "array", "map":
+					return kind, v
+				default:
+					return kind, v // primitive with extra attributes (e.g. logicalType)
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// fullName returns def's namespace-qualified name (e.g. "x.y.Foo"), or its
+// bare name if it has no namespace or is already qualified.
+func fullName(def map[string]interface{}) string {
+	name, _ := def["name"].(string)
+	if strings.ContainsRune(name, '.') {
+		return name
+	}
+	namespace, _ := def["namespace"].(string)
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// unionBranchName returns the string goavro's native union representation
+// keys node's value by: the namespace-qualified name for a record, enum, or
+// fixed, and the bare type keyword for everything else (primitives, arrays,
+// maps). This is distinct from schemaKind's return value, which is always
+// the type keyword and therefore cannot, by itself, identify which union
+// branch produced a given named-type value.
+func unionBranchName(node interface{}) string {
+	kind, def := schemaKind(node)
+	switch kind {
+	case "record", "enum", "fixed":
+		return fullName(def)
+	default:
+		return kind
+	}
+}
+
+// Schema returns the original schema text c was constructed from.
+func (c *Codec) Schema() string {
+	return c.schemaOriginal
+}
+
+// CanonicalSchema returns c's schema rewritten into the Avro specification's
+// Parsing Canonical Form.
+func (c *Codec) CanonicalSchema() string {
+	return c.schemaCanonical
+}
+
+// SchemaCRC64Avro returns the 64 bit Rabin fingerprint of c's canonical
+// schema, computed using the CRC-64-AVRO algorithm the Avro specification
+// recommends for identifying a schema by digest.
+func (c *Codec) SchemaCRC64Avro() int64 {
+	return int64(crc64Avro([]byte(c.schemaCanonical)))
+}
+
+// singleFromNative appends the Avro single-object encoding of datum --
+// the two byte marker 0xC3 0x01, the schema's 8 byte little endian
+// CRC-64-AVRO fingerprint, then the Avro binary encoding of datum -- to buf
+// and returns the resulting byte slice. On error it returns the original
+// buf, not having modified it.
+func (c *Codec) singleFromNative(buf []byte, datum interface{}) ([]byte, error) {
+	body, err := c.BinaryFromNative(nil, datum)
+	if err != nil {
+		return buf, err
+	}
+
+	header := make([]byte, 10)
+	header[0], header[1] = singleObjectMagicBytes[0], singleObjectMagicBytes[1]
+	binary.LittleEndian.PutUint64(header[2:], uint64(c.SchemaCRC64Avro()))
+
+	buf = append(buf, header...)
+	buf = append(buf, body...)
+	return buf, nil
+}
+
+// nativeFromSingle decodes buf, which is expected to be single-object
+// encoded using c's schema, and returns the resulting native Go value along
+// with any remaining bytes.
+func (c *Codec) nativeFromSingle(buf []byte) (interface{}, []byte, error) {
+	if len(buf) < 10 {
+		return nil, buf, fmt.Errorf("cannot decode binary single object encoding: buffer underrun")
+	}
+	if buf[0] != singleObjectMagicBytes[0] || buf[1] != singleObjectMagicBytes[1] {
+		return nil, buf, fmt.Errorf("cannot decode binary single object encoding: invalid marker")
+	}
+	if got, want := binary.LittleEndian.Uint64(buf[2:10]), uint64(c.SchemaCRC64Avro()); got != want {
+		return nil, buf, fmt.Errorf("cannot decode binary single object encoding: fingerprint mismatch")
+	}
+	return c.NativeFromBinary(buf[10:])
+}
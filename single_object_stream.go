@@ -0,0 +1,183 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// fillChunkSize is how many bytes SingleObjectDecoder and MultiSchemaDecoder
+// ask their underlying io.Reader for each time they need more data to
+// complete a frame.
+const fillChunkSize = 4096
+
+// isBufferUnderrun reports whether err is the kind of error
+// nativeFromSingle and NativeFromBinary return when buf does not yet hold a
+// complete encoding, as opposed to a
+// genuine decoding error. Streaming decoders use this to decide whether to
+// read more bytes and retry, or give up and report the error to the caller.
+func isBufferUnderrun(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "buffer underrun")
+}
+
+// SingleObjectDecoder reads a continuous stream of single-object encoded
+// messages, all sharing the same schema, from an io.Reader, such as a Kafka
+// partition consumer socket or a file of concatenated frames.
+type SingleObjectDecoder struct {
+	codec *Codec
+	r     io.Reader
+	buf   []byte
+}
+
+// NewSingleObjectDecoder returns a SingleObjectDecoder that reads single
+// object encoded messages produced against c's schema from r.
+func (c *Codec) NewSingleObjectDecoder(r io.Reader) *SingleObjectDecoder {
+	return &SingleObjectDecoder{codec: c, r: r}
+}
+
+// Decode validates the next frame's 0xC3 0x01 marker and fingerprint,
+// reading more bytes from the underlying io.Reader as needed, and returns
+// its native Go value.
+func (d *SingleObjectDecoder) Decode() (interface{}, error) {
+	for {
+		datum, rest, err := d.codec.nativeFromSingle(d.buf)
+		if err == nil {
+			d.buf = rest
+			return datum, nil
+		}
+		if !isBufferUnderrun(err) {
+			return nil, err
+		}
+		if err := d.fill(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (d *SingleObjectDecoder) fill() error {
+	chunk := make([]byte, fillChunkSize)
+	n, err := d.r.Read(chunk)
+	if n > 0 {
+		d.buf = append(d.buf, chunk[:n]...)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return io.ErrNoProgress
+}
+
+// SingleObjectEncoder writes a continuous stream of single-object encoded
+// messages, all sharing the same schema, to an io.Writer.
+type SingleObjectEncoder struct {
+	codec *Codec
+	w     io.Writer
+}
+
+// NewSingleObjectEncoder returns a SingleObjectEncoder that writes datums
+// encoded against c's schema to w.
+func (c *Codec) NewSingleObjectEncoder(w io.Writer) *SingleObjectEncoder {
+	return &SingleObjectEncoder{codec: c, w: w}
+}
+
+// Encode writes the single object encoding of datum to the underlying
+// io.Writer.
+func (e *SingleObjectEncoder) Encode(datum interface{}) error {
+	buf, err := e.codec.singleFromNative(nil, datum)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(buf)
+	return err
+}
+
+// MultiSchemaDecoder reads a continuous stream of single-object encoded
+// messages that may have been produced against any of several schemas, such
+// as a Kafka topic carrying more than one record type, dispatching each
+// frame to the Codec registered for its embedded fingerprint.
+type MultiSchemaDecoder struct {
+	r      io.Reader
+	buf    []byte
+	lookup func(fingerprint uint64) (*Codec, error)
+}
+
+// NewMultiSchemaDecoder returns a MultiSchemaDecoder that reads from r,
+// resolving each frame's Codec by looking its fingerprint up in codecs.
+func NewMultiSchemaDecoder(r io.Reader, codecs map[uint64]*Codec) *MultiSchemaDecoder {
+	return NewMultiSchemaDecoderFunc(r, func(fingerprint uint64) (*Codec, error) {
+		codec, ok := codecs[fingerprint]
+		if !ok {
+			return nil, fmt.Errorf("cannot decode single object encoding: no codec registered for fingerprint: %#x", fingerprint)
+		}
+		return codec, nil
+	})
+}
+
+// NewMultiSchemaDecoderFunc is identical to NewMultiSchemaDecoder except
+// that it resolves each frame's Codec by calling lookup rather than
+// consulting a fixed map, allowing callers to back the lookup with a
+// FingerprintRegistry, a remote schema registry, or any other source.
+func NewMultiSchemaDecoderFunc(r io.Reader, lookup func(fingerprint uint64) (*Codec, error)) *MultiSchemaDecoder {
+	return &MultiSchemaDecoder{r: r, lookup: lookup}
+}
+
+// Decode reads the next frame, returning the CRC-64-AVRO fingerprint that
+// identified its schema together with its decoded native Go value. Because
+// single object encoding carries no frame length, an unrecognized
+// fingerprint or a decoding error leaves the stream desynchronized: callers
+// must not call Decode again after an error, since there is no way to
+// locate the start of the following frame without first knowing how long
+// the unreadable one was.
+func (d *MultiSchemaDecoder) Decode() (uint64, interface{}, error) {
+	for len(d.buf) < 10 {
+		if err := d.fill(); err != nil {
+			return 0, nil, err
+		}
+	}
+	if d.buf[0] != singleObjectMagicBytes[0] || d.buf[1] != singleObjectMagicBytes[1] {
+		return 0, nil, fmt.Errorf("cannot decode single object encoding: invalid marker")
+	}
+	fingerprint := binary.LittleEndian.Uint64(d.buf[2:10])
+
+	codec, err := d.lookup(fingerprint)
+	if err != nil {
+		return fingerprint, nil, err
+	}
+
+	for {
+		datum, rest, err := codec.nativeFromSingle(d.buf)
+		if err == nil {
+			d.buf = rest
+			return fingerprint, datum, nil
+		}
+		if !isBufferUnderrun(err) {
+			return fingerprint, nil, err
+		}
+		if err := d.fill(); err != nil {
+			return fingerprint, nil, err
+		}
+	}
+}
+
+func (d *MultiSchemaDecoder) fill() error {
+	chunk := make([]byte, fillChunkSize)
+	n, err := d.r.Read(chunk)
+	if n > 0 {
+		d.buf = append(d.buf, chunk[:n]...)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return io.ErrNoProgress
+}
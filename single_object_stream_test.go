@@ -0,0 +1,147 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestSingleObjectEncoderDecoderStream(t *testing.T) {
+	codec, err := NewCodec(`"string"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	enc := codec.NewSingleObjectEncoder(&buf)
+	for _, s := range []string{"alpha", "bravo", "charlie"} {
+		if err := enc.Encode(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dec := codec.NewSingleObjectDecoder(&buf)
+	for _, want := range []string{"alpha", "bravo", "charlie"} {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	}
+
+	if _, err := dec.Decode(); err == nil {
+		t.Fatal("expected error reading past end of stream; got none")
+	}
+}
+
+func TestSingleObjectDecoderReadsAcrossShortReads(t *testing.T) {
+	codec, err := NewCodec(`"long"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := codec.singleFromNative(nil, int64(123456))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := codec.NewSingleObjectDecoder(&oneByteReader{data: buf})
+	datum, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := datum, int64(123456); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+// oneByteReader returns at most one byte per Read call, to exercise the
+// decoders' buffer-underrun retry loop.
+type oneByteReader struct {
+	data []byte
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	p[0] = r.data[0]
+	r.data = r.data[1:]
+	return 1, nil
+}
+
+func TestMultiSchemaDecoderDispatchesByFingerprint(t *testing.T) {
+	intCodec, err := NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stringCodec, err := NewCodec(`"string"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	intEnc := intCodec.NewSingleObjectEncoder(&buf)
+	stringEnc := stringCodec.NewSingleObjectEncoder(&buf)
+	if err := intEnc.Encode(int32(5)); err != nil {
+		t.Fatal(err)
+	}
+	if err := stringEnc.Encode("hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	codecs := map[uint64]*Codec{
+		uint64(intCodec.SchemaCRC64Avro()):    intCodec,
+		uint64(stringCodec.SchemaCRC64Avro()): stringCodec,
+	}
+	dec := NewMultiSchemaDecoder(&buf, codecs)
+
+	fp, datum, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fp, uint64(intCodec.SchemaCRC64Avro()); got != want {
+		t.Errorf("GOT: %#x; WANT: %#x", got, want)
+	}
+	if got, want := datum, int32(5); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	fp, datum, err = dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fp, uint64(stringCodec.SchemaCRC64Avro()); got != want {
+		t.Errorf("GOT: %#x; WANT: %#x", got, want)
+	}
+	if got, want := datum, "hello"; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestMultiSchemaDecoderUnknownFingerprint(t *testing.T) {
+	codec, err := NewCodec(`"int"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := codec.NewSingleObjectEncoder(&buf).Encode(int32(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewMultiSchemaDecoder(&buf, map[uint64]*Codec{})
+	if _, _, err := dec.Decode(); err == nil {
+		t.Fatal("expected error; got none")
+	}
+}
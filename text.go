@@ -0,0 +1,330 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// TextualFromNative appends the Avro JSON text encoding of datum to buf and
+// returns the resulting byte slice. Every field of a record is always
+// emitted, even those whose value equals the field's schema default.
+func (c *Codec) TextualFromNative(buf []byte, datum interface{}) ([]byte, error) {
+	j, err := jsonableFromNativeNode(c.node, datum, c.namedTypes)
+	if err != nil {
+		return buf, err
+	}
+	encoded, err := json.Marshal(j)
+	if err != nil {
+		return buf, fmt.Errorf("cannot encode textual: %s", err)
+	}
+	return append(buf, encoded...), nil
+}
+
+// NativeFromTextual decodes the single Avro JSON text encoded value at the
+// front of buf, returning it in native Go form along with any remaining,
+// unconsumed bytes. A record's fields may be omitted from buf's JSON when
+// the schema gives them a default value, in which case the default is used.
+func (c *Codec) NativeFromTextual(buf []byte) (interface{}, []byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	var raw interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, buf, fmt.Errorf("cannot decode textual: %s", err)
+	}
+	rest := buf[dec.InputOffset():]
+
+	native, err := nativeFromTextualNode(c.node, raw, c.namedTypes)
+	if err != nil {
+		return nil, buf, err
+	}
+	return native, rest, nil
+}
+
+func bytesFromLatin1String(s string) []byte {
+	b := make([]byte, len(s))
+	for i, r := range []rune(s) {
+		b[i] = byte(r)
+	}
+	return b
+}
+
+func latin1StringFromBytes(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, x := range b {
+		runes[i] = rune(x)
+	}
+	return string(runes)
+}
+
+func jsonableFromNativeNode(node interface{}, datum interface{}, registry map[string]interface{}) (interface{}, error) {
+	node = resolveNamedNode(node, registry)
+	kind, def := schemaKind(node)
+
+	switch kind {
+	case "null":
+		if datum != nil {
+			return nil, fmt.Errorf("cannot encode textual null: expected nil, received %T", datum)
+		}
+		return nil, nil
+	case "boolean", "int", "long", "float", "double", "string":
+		return datum, nil
+	case "bytes", "fixed":
+		v, ok := datum.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("cannot encode textual %s: expected []byte, received %T", kind, datum)
+		}
+		return latin1StringFromBytes(v), nil
+	case "enum":
+		v, ok := datum.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot encode textual enum: expected string, received %T", datum)
+		}
+		return v, nil
+	case "record":
+		m, ok := datum.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot encode textual record: expected map[string]interface{}, received %T", datum)
+		}
+		fields, _ := def["fields"].([]interface{})
+		out := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			fm, _ := f.(map[string]interface{})
+			name, _ := fm["name"].(string)
+			v, err := jsonableFromNativeNode(fm["type"], m[name], registry)
+			if err != nil {
+				return nil, fmt.Errorf("cannot encode textual record field %q: %s", name, err)
+			}
+			out[name] = v
+		}
+		return out, nil
+	case "array":
+		v, ok := datum.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot encode textual array: expected []interface{}, received %T", datum)
+		}
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			jv, err := jsonableFromNativeNode(def["items"], item, registry)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = jv
+		}
+		return out, nil
+	case "map":
+		v, ok := datum.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot encode textual map: expected map[string]interface{}, received %T", datum)
+		}
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			jv, err := jsonableFromNativeNode(def["values"], val, registry)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = jv
+		}
+		return out, nil
+	case "union":
+		if datum == nil {
+			return nil, nil
+		}
+		m, ok := datum.(map[string]interface{})
+		if !ok || len(m) != 1 {
+			return nil, fmt.Errorf("cannot encode textual union: expected map[string]interface{} with a single key, received %T", datum)
+		}
+		var branchName string
+		var value interface{}
+		for k, v := range m {
+			branchName, value = k, v
+		}
+		branches, _ := node.([]interface{})
+		for _, b := range branches {
+			if unionBranchName(b) == branchName {
+				jv, err := jsonableFromNativeNode(b, value, registry)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]interface{}{branchName: jv}, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot encode textual union: no branch named %q", branchName)
+	default:
+		return nil, fmt.Errorf("cannot encode textual: unknown type: %q", kind)
+	}
+}
+
+func nativeFromTextualNode(node interface{}, raw interface{}, registry map[string]interface{}) (interface{}, error) {
+	node = resolveNamedNode(node, registry)
+	kind, def := schemaKind(node)
+
+	switch kind {
+	case "null":
+		if raw != nil {
+			return nil, fmt.Errorf("cannot decode textual null: expected null, received %v", raw)
+		}
+		return nil, nil
+	case "boolean":
+		v, ok := raw.(bool)
+		if !ok {
+			return nil, fmt.Errorf("cannot decode textual boolean: expected bool, received %T", raw)
+		}
+		return v, nil
+	case "int":
+		f, ok := raw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot decode textual int: expected number, received %T", raw)
+		}
+		return int32(f), nil
+	case "long":
+		f, ok := raw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot decode textual long: expected number, received %T", raw)
+		}
+		return int64(f), nil
+	case "float":
+		f, ok := raw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot decode textual float: expected number, received %T", raw)
+		}
+		return float32(f), nil
+	case "double":
+		f, ok := raw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot decode textual double: expected number, received %T", raw)
+		}
+		return f, nil
+	case "string":
+		v, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot decode textual string: expected string, received %T", raw)
+		}
+		return v, nil
+	case "bytes", "fixed":
+		v, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot decode textual %s: expected string, received %T", kind, raw)
+		}
+		return bytesFromLatin1String(v), nil
+	case "enum":
+		v, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot decode textual enum: expected string, received %T", raw)
+		}
+		return v, nil
+	case "record":
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot decode textual record: expected JSON object, received %T", raw)
+		}
+		fields, _ := def["fields"].([]interface{})
+		out := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			fm, _ := f.(map[string]interface{})
+			name, _ := fm["name"].(string)
+			if rv, present := m[name]; present {
+				v, err := nativeFromTextualNode(fm["type"], rv, registry)
+				if err != nil {
+					return nil, fmt.Errorf("cannot decode textual record field %q: %s", name, err)
+				}
+				out[name] = v
+			} else if defaultValue, hasDefault := fm["default"]; hasDefault {
+				v, err := nativeFromDefault(fm["type"], defaultValue, registry)
+				if err != nil {
+					return nil, fmt.Errorf("cannot decode textual record field %q default: %s", name, err)
+				}
+				out[name] = v
+			} else {
+				return nil, fmt.Errorf("cannot decode textual record: missing required field %q", name)
+			}
+		}
+		return out, nil
+	case "array":
+		v, ok := raw.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot decode textual array: expected JSON array, received %T", raw)
+		}
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			nv, err := nativeFromTextualNode(def["items"], item, registry)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = nv
+		}
+		return out, nil
+	case "map":
+		v, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot decode textual map: expected JSON object, received %T", raw)
+		}
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			nv, err := nativeFromTextualNode(def["values"], val, registry)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = nv
+		}
+		return out, nil
+	case "union":
+		if raw == nil {
+			return nil, nil
+		}
+		m, ok := raw.(map[string]interface{})
+		if !ok || len(m) != 1 {
+			return nil, fmt.Errorf("cannot decode textual union: expected JSON object with a single key, received %T", raw)
+		}
+		var branchName string
+		var value interface{}
+		for k, v := range m {
+			branchName, value = k, v
+		}
+		branches, _ := node.([]interface{})
+		for _, b := range branches {
+			if unionBranchName(b) == branchName {
+				nv, err := nativeFromTextualNode(b, value, registry)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]interface{}{branchName: nv}, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot decode textual union: no branch named %q", branchName)
+	default:
+		return nil, fmt.Errorf("cannot decode textual: unknown type: %q", kind)
+	}
+}
+
+// nativeFromDefault converts raw, a field's default value as written in the
+// schema, into native Go form. It is identical to nativeFromTextualNode
+// except for a union: per the Avro specification a union field's default is
+// written unwrapped, shaped according to the union's first branch, rather
+// than wrapped in goavro's {branchName: value} union representation.
+func nativeFromDefault(node interface{}, raw interface{}, registry map[string]interface{}) (interface{}, error) {
+	resolved := resolveNamedNode(node, registry)
+	if branches, ok := resolved.([]interface{}); ok {
+		if len(branches) == 0 {
+			return nil, fmt.Errorf("cannot decode default: union has no branches")
+		}
+		first := branches[0]
+		if kind, _ := schemaKind(first); kind == "null" {
+			return nil, nil
+		}
+		v, err := nativeFromDefault(first, raw, registry)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{unionBranchName(first): v}, nil
+	}
+	return nativeFromTextualNode(node, raw, registry)
+}
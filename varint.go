@@ -0,0 +1,46 @@
+// Copyright [2019] LinkedIn Corp. Licensed under the Apache License, Version
+// 2.0 (the "License"); you may not use this file except in compliance with the
+// License.  You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+
+package goavro
+
+import "fmt"
+
+// appendZigzagVarint appends the Avro binary encoding shared by int and
+// long -- a variable length, zigzag encoded varint -- of v to buf and
+// returns the resulting slice.
+func appendZigzagVarint(buf []byte, v int64) []byte {
+	u := (uint64(v) << 1) ^ uint64(v>>63)
+	for u >= 0x80 {
+		buf = append(buf, byte(u)|0x80)
+		u >>= 7
+	}
+	return append(buf, byte(u))
+}
+
+// zigzagVarintFromBuf decodes a single zigzag encoded varint from the front
+// of buf, returning its value along with the remaining, unconsumed bytes.
+func zigzagVarintFromBuf(buf []byte) (int64, []byte, error) {
+	var u uint64
+	var shift uint
+	for i := 0; ; i++ {
+		if i >= len(buf) {
+			return 0, buf, fmt.Errorf("buffer underrun")
+		}
+		if shift >= 64 {
+			return 0, buf, fmt.Errorf("cannot decode binary varint: overflow")
+		}
+		b := buf[i]
+		u |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			v := int64(u>>1) ^ -(int64(u & 1))
+			return v, buf[i+1:], nil
+		}
+		shift += 7
+	}
+}